@@ -1,6 +1,8 @@
 package ecgfp5
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"testing"
 
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
@@ -665,6 +667,24 @@ func TestBasicOps(t *testing.T) {
 	if !p1.AddAffine(p2Affine).Equals(p1.Add(p2)) {
 		t.Fatalf("Affine addition check failed")
 	}
+
+	// In-place variants with the receiver aliasing the operands.
+	q := p1
+	if q.SetAdd(&q, &p2); !q.Equals(p1.Add(p2)) {
+		t.Fatalf("SetAdd aliasing the first operand failed")
+	}
+	q = p2
+	if q.SetAdd(&p1, &q); !q.Equals(p1.Add(p2)) {
+		t.Fatalf("SetAdd aliasing the second operand failed")
+	}
+	q = p1
+	if q.SetAdd(&q, &q); !q.Equals(p1.Double()) {
+		t.Fatalf("SetAdd aliasing both operands failed")
+	}
+	q = p1
+	if q.SetAddAffine(&q, &p2Affine); !q.Equals(p1.Add(p2)) {
+		t.Fatalf("SetAddAffine aliasing the first operand failed")
+	}
 }
 
 func TestDecodeAsWeierstrass(t *testing.T) {
@@ -1433,3 +1453,60 @@ func TestWeierstrassMulAdd2(t *testing.T) {
 		}
 	}
 }
+
+func TestPointMarshal(t *testing.T) {
+	s := SampleScalarCrypto()
+	points := []ECgFp5Point{NEUTRAL_ECgFp5Point, GENERATOR_ECgFp5Point, GENERATOR_ECgFp5Point.Mul(&s)}
+
+	for _, p := range points {
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+		var q ECgFp5Point
+		if err := json.Unmarshal(data, &q); err != nil || !q.Equals(p) {
+			t.Fatalf("JSON round trip of %s failed: %v", data, err)
+		}
+
+		bin, _ := p.MarshalBinary()
+		var fromBin ECgFp5Point
+		if err := fromBin.UnmarshalBinary(bin); err != nil || !fromBin.Equals(p) {
+			t.Fatalf("Binary round trip failed: %v", err)
+		}
+
+		w, _ := DecodeFp5AsWeierstrass(p.Encode())
+		data, err = json.Marshal(w)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+		var v WeierstrassPoint
+		if err := json.Unmarshal(data, &v); err != nil || !v.Equals(w) {
+			t.Fatalf("Weierstrass JSON round trip of %s failed: %v", data, err)
+		}
+		wText, _ := w.MarshalText()
+		pText, _ := p.MarshalText()
+		if string(wText) != string(pText) {
+			t.Fatalf("Expected both point forms to share the same encoding")
+		}
+	}
+
+	// Find an element that is not the encoding of any point.
+	w := gFp5.FromUint64(1)
+	for CanBeDecodedIntoPoint(w) {
+		w = gFp5.Add(w, gFp5.FP5_ONE)
+	}
+	var p ECgFp5Point
+	if err := p.UnmarshalBinary(w.ToLittleEndianBytes()); err == nil {
+		t.Fatalf("Expected invalid point encoding to be rejected")
+	}
+	var wp WeierstrassPoint
+	if err := wp.UnmarshalBinary(w.ToLittleEndianBytes()); err == nil {
+		t.Fatalf("Expected invalid Weierstrass point encoding to be rejected")
+	}
+
+	bin := make([]byte, gFp5.Bytes)
+	binary.LittleEndian.PutUint64(bin, g.ORDER)
+	if err := p.UnmarshalBinary(bin); err == nil {
+		t.Fatalf("Expected non-canonical encoding of the neutral point to be rejected")
+	}
+}
@@ -0,0 +1,153 @@
+package ecgfp5
+
+import (
+	"fmt"
+
+	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+	"github.com/ppd0705/poseidon_crypto/internal/marshal"
+)
+
+// Binary forms:
+//   - ECgFp5Scalar: 40-byte little-endian value, which must be below the group order.
+//   - ECgFp5Point: the gFp5 element returned by Encode, which must decode to a point.
+//   - WeierstrassPoint: same encoding as the corresponding ECgFp5Point.
+// The text forms are the hex of the binary ones and the JSON forms the text as a
+// string.
+
+func (s ECgFp5Scalar) MarshalBinary() ([]byte, error) {
+	return s.ToLittleEndianBytes(), nil
+}
+
+func (s *ECgFp5Scalar) UnmarshalBinary(data []byte) error {
+	res, err := ScalarElementFromCanonicalLittleEndianBytes(data)
+	if err != nil {
+		return err
+	}
+	*s = res
+	return nil
+}
+
+func (s ECgFp5Scalar) MarshalText() ([]byte, error) {
+	return marshal.EncodeHex(s.ToLittleEndianBytes()), nil
+}
+
+func (s *ECgFp5Scalar) UnmarshalText(text []byte) error {
+	data, err := marshal.DecodeHex(text, 40)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(data)
+}
+
+func (s ECgFp5Scalar) MarshalJSON() ([]byte, error) {
+	text, _ := s.MarshalText()
+	return marshal.MarshalJSONText(text)
+}
+
+func (s *ECgFp5Scalar) UnmarshalJSON(data []byte) error {
+	text, err := marshal.UnmarshalJSONText(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return s.UnmarshalText(text)
+}
+
+func decodeCanonicalFp5(data []byte) (gFp5.Element, error) {
+	w, err := gFp5.FromCanonicalLittleEndianBytes(data)
+	if err != nil {
+		return gFp5.Element{}, fmt.Errorf("failed to decode point encoding: %w", err)
+	}
+	return w, nil
+}
+
+func (p ECgFp5Point) MarshalBinary() ([]byte, error) {
+	return p.Encode().ToLittleEndianBytes(), nil
+}
+
+func (p *ECgFp5Point) UnmarshalBinary(data []byte) error {
+	w, err := decodeCanonicalFp5(data)
+	if err != nil {
+		return err
+	}
+	res, ok := Decode(w)
+	if !ok {
+		return fmt.Errorf("invalid point encoding: %s", w.ToString())
+	}
+	*p = res
+	return nil
+}
+
+func (p ECgFp5Point) MarshalText() ([]byte, error) {
+	return marshal.EncodeHex(p.Encode().ToLittleEndianBytes()), nil
+}
+
+func (p *ECgFp5Point) UnmarshalText(text []byte) error {
+	data, err := marshal.DecodeHex(text, gFp5.Bytes)
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalBinary(data)
+}
+
+func (p ECgFp5Point) MarshalJSON() ([]byte, error) {
+	text, _ := p.MarshalText()
+	return marshal.MarshalJSONText(text)
+}
+
+func (p *ECgFp5Point) UnmarshalJSON(data []byte) error {
+	text, err := marshal.UnmarshalJSONText(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return p.UnmarshalText(text)
+}
+
+// The point at infinity encodes to zero regardless of its coordinates.
+func (p WeierstrassPoint) encodeCanonical() gFp5.Element {
+	if p.IsInf {
+		return gFp5.FP5_ZERO
+	}
+	return p.Encode()
+}
+
+func (p WeierstrassPoint) MarshalBinary() ([]byte, error) {
+	return p.encodeCanonical().ToLittleEndianBytes(), nil
+}
+
+func (p *WeierstrassPoint) UnmarshalBinary(data []byte) error {
+	w, err := decodeCanonicalFp5(data)
+	if err != nil {
+		return err
+	}
+	res, ok := DecodeFp5AsWeierstrass(w)
+	if !ok {
+		return fmt.Errorf("invalid point encoding: %s", w.ToString())
+	}
+	*p = res
+	return nil
+}
+
+func (p WeierstrassPoint) MarshalText() ([]byte, error) {
+	return marshal.EncodeHex(p.encodeCanonical().ToLittleEndianBytes()), nil
+}
+
+func (p *WeierstrassPoint) UnmarshalText(text []byte) error {
+	data, err := marshal.DecodeHex(text, gFp5.Bytes)
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalBinary(data)
+}
+
+func (p WeierstrassPoint) MarshalJSON() ([]byte, error) {
+	text, _ := p.MarshalText()
+	return marshal.MarshalJSONText(text)
+}
+
+func (p *WeierstrassPoint) UnmarshalJSON(data []byte) error {
+	text, err := marshal.UnmarshalJSONText(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return p.UnmarshalText(text)
+}
@@ -46,10 +46,46 @@ var (
 )
 
 func (p ECgFp5Point) Equals(rhs ECgFp5Point) bool {
-	return gFp5.Equals(
-		gFp5.Mul(p.u, rhs.t),
-		gFp5.Mul(rhs.u, p.t),
-	)
+	var lhsUT, rhsUT gFp5.Element
+	lhsUT.Mul(&p.u, &rhs.t)
+	rhsUT.Mul(&rhs.u, &p.t)
+	return lhsUT.Equal(&rhsUT)
+}
+
+// Multiplication by the constants c*X of the formulas, c = k*B1: a shift of
+// the coefficients scaled by c, and by W*c for the one wrapping around.
+type xMultiple struct {
+	c, wc g.Element
+}
+
+func newXMultiple(c uint64) xMultiple {
+	return xMultiple{c: g.FromUint64(c), wc: g.FromUint64(3 * c)}
+}
+
+var (
+	mulByB      = newXMultiple(B1)
+	mulByBMul2  = newXMultiple(2 * B1)
+	mulByBMul4  = newXMultiple(4 * B1)
+	mulByBMul16 = newXMultiple(16 * B1)
+
+	// B_MUL4 - 4
+	bMul4Minus4 = gFp5.Sub(B_MUL4_ECgFp5Point, gFp5.FromUint64(4))
+)
+
+// Sets z = x * c*X.
+func (m *xMultiple) mul(z, x *gFp5.Element) *gFp5.Element {
+	x4 := x[4]
+	z[4].Mul(&x[3], &m.c)
+	z[3].Mul(&x[2], &m.c)
+	z[2].Mul(&x[1], &m.c)
+	z[1].Mul(&x[0], &m.c)
+	z[0].Mul(&x4, &m.wc)
+	return z
+}
+
+// Sets z = 4 * x.
+func mulBy4(z, x *gFp5.Element) *gFp5.Element {
+	return z.Double(x).Double(z)
 }
 
 func CanBeDecodedIntoPoint(w gFp5.Element) bool {
@@ -61,8 +97,11 @@ func CanBeDecodedIntoPoint(w gFp5.Element) bool {
 	return gFp5.IsZero(w) || deltaLegendre.IsOne()
 }
 
+// The point is usually derived from a secret (public key, signature nonce), so
+// the inversion runs in constant time.
 func (p ECgFp5Point) Encode() gFp5.Element {
-	return gFp5.Mul(p.t, gFp5.InverseOrZero(p.u))
+	uInv, _ := gFp5.InverseOrZeroCT(p.u)
+	return gFp5.MulCT(p.t, uInv)
 }
 
 // Attempt to decode a point from an gFp5 element
@@ -121,57 +160,57 @@ func (p ECgFp5Point) IsNeutral() bool {
 
 // General point addition. formulas are complete (no special case).
 func (p ECgFp5Point) Add(rhs ECgFp5Point) ECgFp5Point {
+	p.SetAdd(&p, &rhs)
+	return p
+}
+
+// Sets p = a + b. Both operands may alias p.
+func (p *ECgFp5Point) SetAdd(a, b *ECgFp5Point) *ECgFp5Point {
 	// cost: 10M
+	var t1, t2, t3, t4, t5, t6, t7, t8, t9, t10, tmp gFp5.Element
+
+	t1.Mul(&a.x, &b.x)
+	t2.Mul(&a.z, &b.z)
+	t3.Mul(&a.u, &b.u)
+	t4.Mul(&a.t, &b.t)
+	// t5 = (x1 + z1) * (x2 + z2) - t1 - t2
+	t5.Add(&a.x, &a.z)
+	tmp.Add(&b.x, &b.z)
+	t5.Mul(&t5, &tmp).Sub(&t5, &t1).Sub(&t5, &t2)
+	// t6 = (u1 + t1) * (u2 + t2) - t3 - t4
+	t6.Add(&a.u, &a.t)
+	tmp.Add(&b.u, &b.t)
+	t6.Mul(&t6, &tmp).Sub(&t6, &t3).Sub(&t6, &t4)
+
+	p.setAddTail(&t1, &t2, &t3, &t4, &t5, &t6, &t7, &t8, &t9, &t10)
+	return p
+}
 
-	x1 := p.x
-	z1 := p.z
-	u1 := p.u
-	_t1 := p.t
-
-	x2 := rhs.x
-	z2 := rhs.z
-	u2 := rhs.u
-	_t2 := rhs.t
-
-	// let t1 = x1 * x2;
-	t1 := gFp5.Mul(x1, x2)
-	// let t2 = z1 * z2;
-	t2 := gFp5.Mul(z1, z2)
-	// let t3 = u1 * u2;
-	t3 := gFp5.Mul(u1, u2)
-	// let t4 = _t1 * _t2;
-	t4 := gFp5.Mul(_t1, _t2)
-	// let t5 = (x1 + z1) * (x2 + z2) - t1 - t2;
-	t5 := gFp5.Sub(
-		gFp5.Mul(gFp5.Add(x1, z1), gFp5.Add(x2, z2)),
-		gFp5.Add(t1, t2),
-	)
-	// let t6 = (u1 + _t1) * (u2 + _t2) - t3 - t4;
-	t6 := gFp5.Sub(
-		gFp5.Mul(gFp5.Add(u1, _t1), gFp5.Add(u2, _t2)),
-		gFp5.Add(t3, t4),
-	)
-	// let t7 = t1 + t2 * Self::B;
-	t7 := gFp5.Add(t1, gFp5.Mul(t2, B_ECgFp5Point))
-	// let t8 = t4 * t7;
-	t8 := gFp5.Mul(t4, t7)
-	// let t9 = t3 * (t5 * Self::B_MUL2 + t7.double());
-	t9 := gFp5.Mul(
-		t3,
-		gFp5.Add(gFp5.Mul(t5, B_MUL2_ECgFp5Point), gFp5.Double(t7)),
-	)
-	// let t10 = (t4 + t3.double()) * (t5 + t7);
-	t10 := gFp5.Mul(
-		gFp5.Add(t4, gFp5.Double(t3)),
-		gFp5.Add(t5, t7),
-	)
-
-	xNew := gFp5.Mul(gFp5.Sub(t10, t8), B_ECgFp5Point)
-	zNew := gFp5.Sub(t8, t9)
-	uNew := gFp5.Mul(t6, gFp5.Sub(gFp5.Mul(t2, B_ECgFp5Point), t1))
-	tNew := gFp5.Add(t8, t9)
-
-	return ECgFp5Point{x: xNew, z: zNew, u: uNew, t: tNew}
+// Common end of SetAdd and SetAddAffine, from t1..t6. t7..t10 are scratch.
+func (p *ECgFp5Point) setAddTail(t1, t2, t3, t4, t5, t6, t7, t8, t9, t10 *gFp5.Element) {
+	var tmp gFp5.Element
+
+	// t7 = t1 + t2 * B
+	mulByB.mul(t7, t2).Add(t7, t1)
+	// t8 = t4 * t7
+	t8.Mul(t4, t7)
+	// t9 = t3 * (t5 * B_MUL2 + 2 * t7)
+	mulByBMul2.mul(t9, t5)
+	tmp.Double(t7)
+	t9.Add(t9, &tmp).Mul(t9, t3)
+	// t10 = (t4 + 2 * t3) * (t5 + t7)
+	t10.Double(t3).Add(t10, t4)
+	tmp.Add(t5, t7)
+	t10.Mul(t10, &tmp)
+
+	// x = (t10 - t8) * B
+	tmp.Sub(t10, t8)
+	mulByB.mul(&p.x, &tmp)
+	// u = t6 * (t2 * B - t1)
+	mulByB.mul(&tmp, t2).Sub(&tmp, t1)
+	p.u.Mul(t6, &tmp)
+	p.z.Sub(t8, t9)
+	p.t.Add(t8, t9)
 }
 
 func (p ECgFp5Point) Double() ECgFp5Point {
@@ -182,43 +221,25 @@ func (p ECgFp5Point) Double() ECgFp5Point {
 
 func (p *ECgFp5Point) SetDouble() {
 	// cost: 4M+5S
-	x := p.x
-	z := p.z
-	u := p.u
-	t := p.t
-
-	t1 := gFp5.Mul(z, t)
-	t2 := gFp5.Mul(t1, t)
-	x1 := gFp5.Square(t2)
-	z1 := gFp5.Mul(t1, u)
-	t3 := gFp5.Square(u)
-	w1 := gFp5.Sub(
-		t2,
-		gFp5.Mul(
-			t3,
-			gFp5.Double(gFp5.Add(x, z)),
-		),
-	)
-	t4 := gFp5.Square(z1)
-
-	xNew := gFp5.Mul(t4, B_MUL4_ECgFp5Point)
-	zNew := gFp5.Square(w1)
-	uNew := gFp5.Sub(
-		gFp5.Square(gFp5.Add(w1, z1)),
-		gFp5.Add(t4, zNew),
-	)
-	tNew := gFp5.Sub(
-		gFp5.Double(x1),
-		gFp5.Add(
-			gFp5.Mul(t4, gFp5.FromUint64Array([5]uint64{4, 0, 0, 0, 0})),
-			zNew,
-		),
-	)
-
-	p.x = xNew
-	p.z = zNew
-	p.u = uNew
-	p.t = tNew
+	var t1, t2, x1, z1, t3, w1, t4, tmp gFp5.Element
+
+	t1.Mul(&p.z, &p.t)
+	t2.Mul(&t1, &p.t)
+	x1.Square(&t2)
+	z1.Mul(&t1, &p.u)
+	t3.Square(&p.u)
+	// w1 = t2 - 2 * (x + z) * t3
+	tmp.Add(&p.x, &p.z).Double(&tmp)
+	w1.Mul(&tmp, &t3).Sub(&t2, &w1)
+	t4.Square(&z1)
+
+	mulByBMul4.mul(&p.x, &t4)
+	p.z.Square(&w1)
+	// u = (w1 + z1)^2 - t4 - z
+	p.u.Add(&w1, &z1).Square(&p.u).Sub(&p.u, &t4).Sub(&p.u, &p.z)
+	// t = 2 * x1 - 4 * t4 - z
+	mulBy4(&tmp, &t4)
+	p.t.Double(&x1).Sub(&p.t, &tmp).Sub(&p.t, &p.z)
 }
 
 func (p *ECgFp5Point) MDouble(n uint32) ECgFp5Point {
@@ -237,115 +258,83 @@ func (p *ECgFp5Point) SetMDouble(n uint32) {
 	}
 
 	// cost: n*(2M+5S) + 2M+1S
-	x0 := p.x
-	z0 := p.z
-	u0 := p.u
-	t0 := p.t
-
-	t1 := gFp5.Mul(z0, t0)
-	t2 := gFp5.Mul(t1, t0)
-	x1 := gFp5.Square(t2)
-	z1 := gFp5.Mul(t1, u0)
-	t3 := gFp5.Square(u0)
-	w1 := gFp5.Sub(
-		t2,
-		gFp5.Mul(
-			gFp5.Double(gFp5.Add(x0, z0)),
-			t3,
-		),
-	)
-	t4 := gFp5.Square(w1)
-	t5 := gFp5.Square(z1)
-	x := gFp5.Mul(gFp5.Square(t5), B_MUL16_ECgFp5Point)
-	w := gFp5.Sub(
-		gFp5.Double(x1),
-		gFp5.Add(
-			gFp5.Mul(t5, gFp5.FromUint64Array([5]uint64{4, 0, 0, 0, 0})),
-			t4,
-		),
-	)
-	z := gFp5.Sub(
-		gFp5.Square(gFp5.Add(w1, z1)),
-		gFp5.Add(t4, t5),
-	)
+	var t1, t2, x1, z1, t3, w1, t4, t5, x, w, z, tmp gFp5.Element
+
+	t1.Mul(&p.z, &p.t)
+	t2.Mul(&t1, &p.t)
+	x1.Square(&t2)
+	z1.Mul(&t1, &p.u)
+	t3.Square(&p.u)
+	// w1 = t2 - 2 * (x0 + z0) * t3
+	tmp.Add(&p.x, &p.z).Double(&tmp)
+	w1.Mul(&tmp, &t3).Sub(&t2, &w1)
+	t4.Square(&w1)
+	t5.Square(&z1)
+	// x = t5^2 * B_MUL16
+	tmp.Square(&t5)
+	mulByBMul16.mul(&x, &tmp)
+	// w = 2 * x1 - 4 * t5 - t4
+	mulBy4(&tmp, &t5)
+	w.Double(&x1).Sub(&w, &tmp).Sub(&w, &t4)
+	// z = (w1 + z1)^2 - t4 - t5
+	z.Add(&w1, &z1).Square(&z).Sub(&z, &t4).Sub(&z, &t5)
 
 	for i := 2; i < int(n); i++ {
-		t1 = gFp5.Square(z)
-		t2 = gFp5.Square(t1)
-		t3 = gFp5.Square(w)
-		t4 = gFp5.Square(t3)
-		t5 = gFp5.Sub(
-			gFp5.Square(gFp5.Add(w, z)),
-			gFp5.Add(t1, t3),
-		)
-		z = gFp5.Mul(
-			t5,
-			gFp5.Sub(
-				gFp5.Double(gFp5.Add(x, t1)),
-				t3,
-			),
-		)
-		x = gFp5.Mul(gFp5.Mul(t2, t4), B_MUL16_ECgFp5Point)
-		w = gFp5.Neg(
-			gFp5.Add(
-				t4,
-				gFp5.Mul(
-					t2,
-					gFp5.Sub(
-						B_MUL4_ECgFp5Point,
-						gFp5.FromUint64Array([5]uint64{4, 0, 0, 0, 0}),
-					),
-				),
-			),
-		)
+		t1.Square(&z)
+		t2.Square(&t1)
+		t3.Square(&w)
+		t4.Square(&t3)
+		// t5 = (w + z)^2 - t1 - t3
+		t5.Add(&w, &z).Square(&t5).Sub(&t5, &t1).Sub(&t5, &t3)
+		// z = t5 * (2 * (x + t1) - t3)
+		tmp.Add(&x, &t1).Double(&tmp).Sub(&tmp, &t3)
+		z.Mul(&t5, &tmp)
+		// x = t2 * t4 * B_MUL16
+		tmp.Mul(&t2, &t4)
+		mulByBMul16.mul(&x, &tmp)
+		// w = -(t4 + t2 * (B_MUL4 - 4))
+		w.Mul(&t2, &bMul4Minus4).Add(&w, &t4).Neg(&w)
 	}
 
-	t1 = gFp5.Square(w)
-	t2 = gFp5.Square(z)
-	t3 = gFp5.Sub(
-		gFp5.Square(gFp5.Add(w, z)),
-		gFp5.Add(t1, t2),
-	)
-	w1 = gFp5.Sub(
-		t1,
-		gFp5.Double(gFp5.Add(x, t2)),
-	)
-
-	p.x = gFp5.Mul(gFp5.Square(t3), B_ECgFp5Point)
-	p.z = gFp5.Square(w1)
-	p.u = gFp5.Mul(t3, w1)
-	p.t = gFp5.Sub(
-		gFp5.Mul(
-			gFp5.Double(t1),
-			gFp5.Sub(t1, gFp5.Double(t2)),
-		),
-		p.z,
-	)
+	t1.Square(&w)
+	t2.Square(&z)
+	// t3 = (w + z)^2 - t1 - t2
+	t3.Add(&w, &z).Square(&t3).Sub(&t3, &t1).Sub(&t3, &t2)
+	// w1 = t1 - 2 * (x + t2)
+	tmp.Add(&x, &t2).Double(&tmp)
+	w1.Sub(&t1, &tmp)
+
+	tmp.Square(&t3)
+	mulByB.mul(&p.x, &tmp)
+	p.z.Square(&w1)
+	p.u.Mul(&t3, &w1)
+	// t = 2 * t1 * (t1 - 2 * t2) - z
+	tmp.Double(&t2).Sub(&t1, &tmp)
+	p.t.Double(&t1).Mul(&p.t, &tmp).Sub(&p.t, &p.z)
 }
 
 // Add a point in affine coordinates to this one.
 func (p ECgFp5Point) AddAffine(rhs AffinePoint) ECgFp5Point {
+	p.SetAddAffine(&p, &rhs)
+	return p
+}
+
+// Sets p = a + b. a may alias p.
+func (p *ECgFp5Point) SetAddAffine(a *ECgFp5Point, b *AffinePoint) *ECgFp5Point {
 	// cost: 8M
-	x1, z1, u1, _t1 := p.x, p.z, p.u, p.t
-	x2, u2 := rhs.x, rhs.u
-
-	t1 := gFp5.Mul(x1, x2)
-	t2 := z1
-	t3 := gFp5.Mul(u1, u2)
-	t4 := _t1
-	t5 := gFp5.Add(x1, gFp5.Mul(x2, z1))
-	t6 := gFp5.Add(u1, gFp5.Mul(u2, _t1))
-	t7 := gFp5.Add(t1, gFp5.Mul(t2, B_ECgFp5Point))
-	t8 := gFp5.Mul(t4, t7)
-	t9 := gFp5.Mul(t3, gFp5.Add(gFp5.Mul(t5, B_MUL2_ECgFp5Point), gFp5.Double(t7)))
-	t10 := gFp5.Mul(gFp5.Add(t4, gFp5.Double(t3)), gFp5.Add(t5, t7))
-
-	return ECgFp5Point{
-		x: gFp5.Mul(gFp5.Sub(t10, t8), B_ECgFp5Point),
-		u: gFp5.Mul(t6, gFp5.Sub(gFp5.Mul(t2, B_ECgFp5Point), t1)),
-		z: gFp5.Sub(t8, t9),
-		t: gFp5.Add(t8, t9),
-	}
+	var t1, t2, t3, t4, t5, t6, t7, t8, t9, t10 gFp5.Element
+
+	t1.Mul(&a.x, &b.x)
+	t2 = a.z
+	t3.Mul(&a.u, &b.u)
+	t4 = a.t
+	// t5 = x1 + x2 * z1
+	t5.Mul(&b.x, &a.z).Add(&t5, &a.x)
+	// t6 = u1 + u2 * t1
+	t6.Mul(&b.u, &a.t).Add(&t6, &a.u)
+
+	p.setAddTail(&t1, &t2, &t3, &t4, &t5, &t6, &t7, &t8, &t9, &t10)
+	return p
 }
 
 const (
@@ -367,41 +356,40 @@ func BatchToAffine(src []ECgFp5Point) []AffinePoint {
 	}
 	if n == 1 {
 		p := src[0]
-		m1 := gFp5.InverseOrZero(gFp5.Mul(p.z, p.t))
-		return []AffinePoint{
-			{
-				x: gFp5.Mul(gFp5.Mul(p.x, p.t), m1),
-				u: gFp5.Mul(gFp5.Mul(p.u, p.z), m1),
-			},
-		}
+		var m1 gFp5.Element
+		m1.Mul(&p.z, &p.t)
+		m1 = gFp5.InverseOrZero(m1)
+		var res AffinePoint
+		res.x.Mul(&p.x, &p.t).Mul(&res.x, &m1)
+		res.u.Mul(&p.u, &p.z).Mul(&res.u, &m1)
+		return []AffinePoint{res}
 	}
 
 	res := make([]AffinePoint, n)
 	// Compute product of all values to invert, and invert it.
 	// We also use the x and u coordinates of the points in the
 	// destination slice to keep track of the partial products.
-	m := gFp5.Mul(src[0].z, src[0].t)
+	var m gFp5.Element
+	m.Mul(&src[0].z, &src[0].t)
 	for i := 1; i < n; i++ {
-		x := m
-		m = gFp5.Mul(m, src[i].z)
-		u := m
-		m = gFp5.Mul(m, src[i].t)
-
-		res[i] = AffinePoint{x: x, u: u}
+		res[i].x = m
+		m.Mul(&m, &src[i].z)
+		res[i].u = m
+		m.Mul(&m, &src[i].t)
 	}
 
 	m = gFp5.InverseOrZero(m)
 
 	// Propagate back inverses.
 	for i := n - 1; i > 0; i-- {
-		res[i].u = gFp5.Mul(gFp5.Mul(src[i].u, res[i].u), m)
-		m = gFp5.Mul(m, src[i].t)
-		res[i].x = gFp5.Mul(gFp5.Mul(src[i].x, res[i].x), m)
-		m = gFp5.Mul(m, src[i].z)
+		res[i].u.Mul(&src[i].u, &res[i].u).Mul(&res[i].u, &m)
+		m.Mul(&m, &src[i].t)
+		res[i].x.Mul(&src[i].x, &res[i].x).Mul(&res[i].x, &m)
+		m.Mul(&m, &src[i].z)
 	}
-	res[0].u = gFp5.Mul(gFp5.Mul(src[0].u, src[0].z), m)
-	m = gFp5.Mul(m, src[0].t)
-	res[0].x = gFp5.Mul(src[0].x, m)
+	res[0].u.Mul(&src[0].u, &src[0].z).Mul(&res[0].u, &m)
+	m.Mul(&m, &src[0].t)
+	res[0].x.Mul(&src[0].x, &m)
 
 	return res
 }
@@ -411,9 +399,10 @@ func (p ECgFp5Point) MakeWindowAffine() []AffinePoint {
 	tmp[0] = p
 	for i := 1; i < WIN_SIZE; i++ {
 		if (i & 1) == 0 {
-			tmp[i] = tmp[i-1].Add(p)
+			tmp[i].SetAdd(&tmp[i-1], &p)
 		} else {
-			tmp[i] = tmp[i>>1].Double()
+			tmp[i] = tmp[i>>1]
+			tmp[i].SetDouble()
 		}
 	}
 	return BatchToAffine(tmp)
@@ -430,7 +419,7 @@ func (p *ECgFp5Point) SetMul(s *ECgFp5Scalar) {
 	for i := len(digits) - 2; i >= 0; i-- {
 		p.SetMDouble(uint32(WINDOW))
 		lookup := Lookup(win, digits[i])
-		*p = p.AddAffine(lookup)
+		p.SetAddAffine(p, &lookup)
 	}
 }
 
@@ -2,12 +2,15 @@ package ecgfp5
 
 import (
 	cryptorand "crypto/rand"
-	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
+	"io"
 	"math/big"
-	"math/rand"
+
+	"golang.org/x/crypto/sha3"
 
 	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+	"github.com/ppd0705/poseidon_crypto/internal/batch"
 )
 
 // ECgFp5Scalar represents the scalar field of the ECgFP5 elliptic curve where
@@ -38,6 +41,20 @@ func ScalarElementFromLittleEndianBytes(data []byte) ECgFp5Scalar {
 	return value
 }
 
+// Like ScalarElementFromLittleEndianBytes, but returns an error instead of
+// panicking and rejects values that are not below the group order.
+func ScalarElementFromCanonicalLittleEndianBytes(data []byte) (ECgFp5Scalar, error) {
+	if len(data) != 40 {
+		return ECgFp5Scalar{}, fmt.Errorf("scalar length should be 40 but is %d", len(data))
+	}
+
+	value := ScalarElementFromLittleEndianBytes(data)
+	if _, borrow := value.SubInner(&N); borrow == 0 {
+		return ECgFp5Scalar{}, fmt.Errorf("non-canonical scalar encoding: value is not below the group order")
+	}
+	return value, nil
+}
+
 func (s ECgFp5Scalar) SplitTo4BitLimbs() [80]uint8 {
 	limbs := s[:]
 	var result [80]uint8
@@ -50,27 +67,47 @@ func (s ECgFp5Scalar) SplitTo4BitLimbs() [80]uint8 {
 }
 
 func SampleScalarCrypto() ECgFp5Scalar {
-	rng, err := cryptorand.Int(cryptorand.Reader, ORDER)
+	s, err := SampleScalarFrom(cryptorand.Reader)
 	if err != nil {
 		panic("failed to read random bytes into buffer")
 	}
-	return FromNonCanonicalBigInt(rng)
+	return s
+}
+
+// Samples a uniform scalar from the bytes of r by rejection sampling: 40
+// little-endian bytes are truncated to the 319 bits of the group order and
+// redrawn until the value is below it. The order is close to 2^319, so a draw
+// is almost always accepted.
+func SampleScalarFrom(r io.Reader) (ECgFp5Scalar, error) {
+	var buf [40]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return ECgFp5Scalar{}, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		value := ScalarElementFromLittleEndianBytes(buf[:])
+		value[4] &= 0x7FFFFFFFFFFFFFFF
+		if _, borrow := value.SubInner(&N); borrow != 0 {
+			return value, nil
+		}
+	}
 }
 
+// Samples a scalar from crypto/rand, or deterministically from the seed when it
+// is not nil. The seeded path reads from SHAKE256 over a domain separator and
+// the seed, so the same seed always yields the same scalar.
 func SampleScalar(seed *string) ECgFp5Scalar {
-	var rng *rand.Rand
 	if seed == nil {
 		return SampleScalarCrypto()
 	}
 
-	hash := sha256.Sum256([]byte(*seed))
-	var intSeed int64
-	for _, b := range hash[:8] {
-		intSeed = (intSeed << 8) | int64(b)
+	xof := sha3.NewShake256()
+	xof.Write([]byte("ECgFp5 SampleScalar seed"))
+	xof.Write([]byte(*seed))
+	s, err := SampleScalarFrom(xof)
+	if err != nil {
+		panic(err)
 	}
-	rng = rand.New(rand.NewSource(intSeed))
-
-	return FromNonCanonicalBigInt(new(big.Int).Rand(rng, ORDER))
+	return s
 }
 
 var (
@@ -269,6 +306,42 @@ func (s ECgFp5Scalar) expPowerOf2(exp int) ECgFp5Scalar {
 	return result
 }
 
+// Fermat inversion s^(n-2) mod n, computed in the Montgomery domain.
+// Returns zero if s is zero.
+func (s ECgFp5Scalar) InverseOrZero() ECgFp5Scalar {
+	exp := N
+	exp[0] -= 2
+
+	x := s.MontyMul(&R2) // s*2^320 mod n
+	r := ONE.MontyMul(&R2)
+	for i := 319; i >= 0; i-- {
+		r = r.MontyMul(r)
+		if (exp[i>>6]>>uint(i&63))&1 == 1 {
+			r = r.MontyMul(x)
+		}
+	}
+
+	return *r.MontyMul(&ONE)
+}
+
+// Slices shorter than this are inverted on the calling goroutine.
+const batchInverseMinChunk = 1 << 10
+
+// Inverts every scalar of the slice using Montgomery's trick. Zero scalars are
+// skipped and map to zero in the result.
+func BatchInverseScalar(in []ECgFp5Scalar) []ECgFp5Scalar {
+	res := make([]ECgFp5Scalar, len(in))
+	batch.ParallelChunks(len(in), batchInverseMinChunk, func(start, end int) {
+		batch.InverseChunk(in[start:end], res[start:end],
+			func(e *ECgFp5Scalar) bool { return e.IsZero() },
+			func(a, b *ECgFp5Scalar) ECgFp5Scalar { return *a.Mul(b) },
+			func(e *ECgFp5Scalar) ECgFp5Scalar { return e.InverseOrZero() },
+			ONE,
+		)
+	})
+	return res
+}
+
 func FromGfp5(fp5 gFp5.Element) ECgFp5Scalar {
 	return FromNonCanonicalBigInt(BigIntFromArray([5]uint64{
 		fp5[0].Uint64(), fp5[1].Uint64(), fp5[2].Uint64(), fp5[3].Uint64(), fp5[4].Uint64(),
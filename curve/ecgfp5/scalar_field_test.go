@@ -1,8 +1,14 @@
 package ecgfp5
 
 import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"runtime"
 	"testing"
 
+	"golang.org/x/crypto/sha3"
+
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
 	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
 )
@@ -180,6 +186,49 @@ func TestMul(t *testing.T) {
 	}
 }
 
+func TestInverseOrZero(t *testing.T) {
+	if inv := ZERO.InverseOrZero(); !inv.IsZero() {
+		t.Fatalf("Expected inverse of zero to be zero, but got %v", inv)
+	}
+
+	for i := 0; i < 16; i++ {
+		s := SampleScalarCrypto()
+		inv := s.InverseOrZero()
+
+		expected := FromNonCanonicalBigInt(new(big.Int).ModInverse(s.ToCanonicalBigInt(), ORDER))
+		if !inv.Equals(&expected) {
+			t.Fatalf("Expected inverse of %v to be %v, but got %v", s, expected, inv)
+		}
+		if one := s.Mul(&inv); !one.Equals(&ONE) {
+			t.Fatalf("Expected s * s^-1 to be one, but got %v", one)
+		}
+	}
+}
+
+func TestBatchInverseScalar(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, n := range []int{0, 1, 7, 3 * batchInverseMinChunk} {
+		in := make([]ECgFp5Scalar, n)
+		for i := range in {
+			if i%5 != 3 {
+				in[i] = SampleScalarCrypto()
+			}
+		}
+
+		res := BatchInverseScalar(in)
+		if len(res) != n {
+			t.Fatalf("Expected %d inverses, but got %d", n, len(res))
+		}
+		for i := range in {
+			expected := in[i].InverseOrZero()
+			if !res[i].Equals(&expected) {
+				t.Fatalf("Expected inverse at index %d to be %v, but got %v", i, expected, res[i])
+			}
+		}
+	}
+}
+
 func TestRecodeSigned(t *testing.T) {
 	var ss [50]int32
 	scalar := ECgFp5Scalar{
@@ -727,3 +776,77 @@ func TestSubShifted640(t *testing.T) {
 		}
 	}
 }
+
+func TestScalarMarshal(t *testing.T) {
+	s := SampleScalarCrypto()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	var fromJSON ECgFp5Scalar
+	if err := json.Unmarshal(data, &fromJSON); err != nil || !fromJSON.Equals(&s) {
+		t.Fatalf("JSON round trip of %s failed: %v", data, err)
+	}
+
+	bin, _ := s.MarshalBinary()
+	var fromBin ECgFp5Scalar
+	if err := fromBin.UnmarshalBinary(bin); err != nil || !fromBin.Equals(&s) {
+		t.Fatalf("Binary round trip failed: %v", err)
+	}
+
+	// s + N encodes the same scalar and fits in 320 bits.
+	nonCanonical := s.AddInner(N)
+	if err := fromBin.UnmarshalBinary(nonCanonical.ToLittleEndianBytes()); err == nil {
+		t.Fatalf("Expected non-canonical scalar to be rejected")
+	}
+	if err := fromBin.UnmarshalBinary(N.ToLittleEndianBytes()); err == nil {
+		t.Fatalf("Expected the group order to be rejected")
+	}
+	if err := fromBin.UnmarshalBinary(NEG_ONE.ToLittleEndianBytes()); err != nil || !fromBin.Equals(&NEG_ONE) {
+		t.Fatalf("Expected n-1 to be accepted: %v", err)
+	}
+}
+
+func TestSampleScalarFrom(t *testing.T) {
+	// N itself and values with the 320th bit set are redrawn, after masking
+	// the latter is the valid N - 1.
+	buf := append(N.ToLittleEndianBytes(), NEG_ONE.ToLittleEndianBytes()...)
+	buf[len(buf)-1] |= 0x80
+	s, err := SampleScalarFrom(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatalf("SampleScalarFrom failed: %v", err)
+	}
+	if !s.Equals(&NEG_ONE) {
+		t.Fatalf("Expected %v, got %v", NEG_ONE, s)
+	}
+
+	if _, err := SampleScalarFrom(bytes.NewReader(N.ToLittleEndianBytes())); err == nil {
+		t.Fatalf("Expected an error once the reader is exhausted")
+	}
+
+	xof := sha3.NewShake256()
+	for i := 0; i < 64; i++ {
+		s, err := SampleScalarFrom(xof)
+		if err != nil {
+			t.Fatalf("SampleScalarFrom failed: %v", err)
+		}
+		if s.ToCanonicalBigInt().Cmp(ORDER) >= 0 {
+			t.Fatalf("Sampled scalar %v is not below the order", s)
+		}
+	}
+}
+
+func TestSampleScalarSeeded(t *testing.T) {
+	seed, otherSeed := "seed", "other seed"
+	s1, s2 := SampleScalar(&seed), SampleScalar(&seed)
+	if !s1.Equals(&s2) {
+		t.Fatalf("Expected the same seed to give the same scalar, got %v and %v", s1, s2)
+	}
+	if s3 := SampleScalar(&otherSeed); s1.Equals(&s3) {
+		t.Fatalf("Expected different seeds to give different scalars")
+	}
+	if s1.ToCanonicalBigInt().Cmp(ORDER) >= 0 {
+		t.Fatalf("Sampled scalar %v is not below the order", s1)
+	}
+}
@@ -1,15 +1,23 @@
 package field
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"math"
 	"testing"
 
+	"golang.org/x/crypto/sha3"
+
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
 	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
 
 	"math/big"
 	"math/rand/v2"
+	"runtime"
 )
 
 func TestBytes(t *testing.T) {
@@ -61,6 +69,328 @@ func TestBytesF(t *testing.T) {
 	}
 }
 
+func TestBytesStrictF(t *testing.T) {
+	f := g.GoldilocksField(rand.Uint64N(g.ORDER))
+	ff, err := g.FromCanonicalLittleEndianBytesStrictF(g.ToLittleEndianBytesF(f))
+	if err != nil {
+		t.Fatalf("Failed to decode canonical bytes: %v", err)
+	}
+	if ff != f {
+		t.Fatalf("bytes do not match")
+	}
+
+	// x and x + ORDER encode the same element, only the former is accepted.
+	for _, x := range []uint64{0, 1, g.EPSILON - 2, g.ORDER - 1, math.MaxUint64 - g.ORDER} {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, x)
+		if _, err := g.FromCanonicalLittleEndianBytesStrictF(b); err != nil {
+			t.Fatalf("Expected %d to be accepted, got error: %v", x, err)
+		}
+		if x > math.MaxUint64-g.ORDER {
+			continue // x + ORDER overflows
+		}
+
+		binary.LittleEndian.PutUint64(b, x+g.ORDER)
+		if _, err := g.FromCanonicalLittleEndianBytesStrictF(b); err == nil {
+			t.Fatalf("Expected non-canonical encoding of %d to be rejected", x)
+		}
+	}
+
+	for _, n := range []int{0, 7, 9, 16} {
+		if _, err := g.FromCanonicalLittleEndianBytesStrictF(make([]byte, n)); err == nil {
+			t.Fatalf("Expected %d bytes to be rejected", n)
+		}
+	}
+
+	// The deprecated decoder keeps returning the raw value.
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, 42+g.ORDER)
+	if x := g.FromCanonicalLittleEndianBytesF(b); uint64(x) != 42+g.ORDER {
+		t.Fatalf("Expected the raw value %d, got %d", 42+g.ORDER, uint64(x))
+	}
+
+	valid := g.ToLittleEndianBytesF(g.GoldilocksField(5))
+	invalid := make([]byte, 8)
+	binary.LittleEndian.PutUint64(invalid, g.ORDER)
+	if _, err := g.ArrayFromCanonicalLittleEndianBytesF(append(valid, invalid...)); err == nil {
+		t.Fatalf("Expected array with a non-canonical element to be rejected")
+	}
+	if _, err := g.ArrayFromCanonicalLittleEndianBytesF(append(valid, 1)); err == nil {
+		t.Fatalf("Expected array of 9 bytes to be rejected")
+	}
+	arr, err := g.ArrayFromCanonicalLittleEndianBytesF(append(valid, valid...))
+	if err != nil || len(arr) != 2 || arr[0] != 5 || arr[1] != 5 {
+		t.Fatalf("Failed to decode array of canonical encodings: %v %v", arr, err)
+	}
+}
+
+func seededReader(seed string) sha3.ShakeHash {
+	xof := sha3.NewShake256()
+	xof.Write([]byte(seed))
+	return xof
+}
+
+func TestSampleFrom(t *testing.T) {
+	if !g.ORDER_BIG.IsUint64() || g.ORDER_BIG.Uint64() != g.ORDER {
+		t.Fatalf("ORDER_BIG is %v", g.ORDER_BIG)
+	}
+	if x := g.SampleF(); x.ToCanonicalUint64() != uint64(x) {
+		t.Fatalf("SampleF returned the non-canonical %d", x)
+	}
+
+	// The same seed gives the same elements in both representations.
+	e, err := g.SampleFrom(seededReader("seed"))
+	if err != nil {
+		t.Fatalf("SampleFrom failed: %v", err)
+	}
+	f, err := g.SampleFFrom(seededReader("seed"))
+	if err != nil {
+		t.Fatalf("SampleFFrom failed: %v", err)
+	}
+	if e.Uint64() != uint64(f) {
+		t.Fatalf("Expected the same element, got %d and %d", e.Uint64(), f)
+	}
+	arr, err := g.RandArrayFrom(seededReader("seed"), 5)
+	if err != nil || arr[0] != e {
+		t.Fatalf("Expected RandArrayFrom to start with %v, got %v (%v)", e, arr, err)
+	}
+	ext, err := gFp5.SampleFrom(seededReader("seed"))
+	if err != nil || ext.ToUint64Array() != [5]uint64{arr[0].Uint64(), arr[1].Uint64(), arr[2].Uint64(), arr[3].Uint64(), arr[4].Uint64()} {
+		t.Fatalf("Expected gFp5.SampleFrom to match RandArrayFrom, got %v (%v)", ext, err)
+	}
+	if other, _ := g.SampleFFrom(seededReader("other seed")); other == f {
+		t.Fatalf("Expected different seeds to give different elements")
+	}
+
+	// Values not below ORDER are rejected and the next 8 bytes are used.
+	buf := make([]byte, 24)
+	binary.LittleEndian.PutUint64(buf[0:], g.ORDER)
+	binary.LittleEndian.PutUint64(buf[8:], math.MaxUint64)
+	binary.LittleEndian.PutUint64(buf[16:], g.ORDER-1)
+	f, err = g.SampleFFrom(bytes.NewReader(buf))
+	if err != nil || uint64(f) != g.ORDER-1 {
+		t.Fatalf("Expected %d, got %d (%v)", g.ORDER-1, f, err)
+	}
+
+	if _, err := g.SampleFFrom(bytes.NewReader(buf[:16])); err == nil {
+		t.Fatalf("Expected an error once the reader is exhausted")
+	}
+	if _, err := gFp5.SampleFrom(bytes.NewReader(make([]byte, 4*g.Bytes))); err == nil {
+		t.Fatalf("Expected an error on a short reader")
+	}
+}
+
+// Test vectors of RFC 9380, appendix K.1.
+func TestExpandMessageXMD(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	tests := []struct {
+		msg        string
+		lenInBytes int
+		expected   string
+	}{
+		{"", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"abcdef0123456789", 0x20, "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+		{"", 0x80, "af84c27ccfd45d41914fdff5df25293e221afc53d8ad2ac06d5e3e29485dadbee0d121587713a3e0dd4d5e69e93eb7cd4f5df4cd103e188cf60cb02edc3edf18eda8576c412b18ffb658e3dd6ec849469b979d444cf7b26911a08e63cf31f9dcc541708d3491184472c2c29bb749d4286b004ceb5ee6b9a7fa5b646c993f0ced"},
+	}
+	for _, test := range tests {
+		res, err := g.ExpandMessageXMD([]byte(test.msg), dst, test.lenInBytes)
+		if err != nil {
+			t.Fatalf("ExpandMessageXMD(%q) failed: %v", test.msg, err)
+		}
+		if hex.EncodeToString(res) != test.expected {
+			t.Fatalf("ExpandMessageXMD(%q): expected %s, got %x", test.msg, test.expected, res)
+		}
+	}
+
+	// Lengths that are not a multiple of the SHA-256 output size.
+	short, err := g.ExpandMessageXMD([]byte("abc"), dst, 24)
+	if err != nil || len(short) != 24 {
+		t.Fatalf("Expected 24 bytes, got %x (%v)", short, err)
+	}
+	if _, err := g.ExpandMessageXMD(nil, dst, 8161); err == nil {
+		t.Fatalf("Expected more than 8160 bytes to be rejected")
+	}
+}
+
+func TestHashToField(t *testing.T) {
+	msg, dst := []byte("abc"), []byte("POSEIDON-CRYPTO-V01-TEST")
+
+	// hash_to_field of RFC 9380: each element is L big-endian bytes of
+	// expand_message_xmd reduced modulo the order.
+	uniform, err := g.ExpandMessageXMD(msg, dst, 10*g.HashToFieldL)
+	if err != nil {
+		t.Fatalf("ExpandMessageXMD failed: %v", err)
+	}
+	expected := make([]uint64, 10)
+	for i := range expected {
+		chunk := new(big.Int).SetBytes(uniform[i*g.HashToFieldL : (i+1)*g.HashToFieldL])
+		expected[i] = chunk.Mod(chunk, g.ORDER_BIG).Uint64()
+	}
+
+	elems, err := g.HashToField(msg, dst, 10)
+	if err != nil {
+		t.Fatalf("HashToField failed: %v", err)
+	}
+	elemsF, err := g.HashToFieldF(msg, dst, 10)
+	if err != nil {
+		t.Fatalf("HashToFieldF failed: %v", err)
+	}
+	exts, err := gFp5.HashToField(msg, dst, 2)
+	if err != nil {
+		t.Fatalf("gFp5.HashToField failed: %v", err)
+	}
+	for i := range expected {
+		if elems[i].Uint64() != expected[i] || uint64(elemsF[i]) != expected[i] {
+			t.Fatalf("Element %d: expected %d, got %d and %d", i, expected[i], elems[i].Uint64(), elemsF[i])
+		}
+		if exts[i/5][i%5].Uint64() != expected[i] {
+			t.Fatalf("Coefficient %d of extension element %d: expected %d, got %d", i%5, i/5, expected[i], exts[i/5][i%5].Uint64())
+		}
+	}
+
+	other, err := g.HashToFieldF(msg, []byte("POSEIDON-CRYPTO-V01-OTHER"), 1)
+	if err != nil || other[0] == elemsF[0] {
+		t.Fatalf("Expected different tags to give different elements, got %v (%v)", other, err)
+	}
+
+	if _, err := g.HashToField(msg, nil, 1); err == nil {
+		t.Fatalf("Expected an empty tag to be rejected")
+	}
+	if _, err := g.HashToFieldF(msg, make([]byte, 256), 1); err == nil {
+		t.Fatalf("Expected a tag longer than 255 bytes to be rejected")
+	}
+	if _, err := gFp5.HashToField(msg, dst, 69); err == nil {
+		t.Fatalf("Expected more than 68 extension elements to be rejected")
+	}
+	if _, err := gFp5.HashToField(msg, dst, 68); err != nil {
+		t.Fatalf("Expected 68 extension elements to be accepted, got %v", err)
+	}
+}
+
+func TestPackBytes(t *testing.T) {
+	seen := map[string]string{}
+	for n := 0; n <= 3*g.PackedBytesPerElement+1; n++ {
+		for _, fill := range []byte{0x00, 0xff} {
+			b := bytes.Repeat([]byte{fill}, n)
+
+			packed := g.PackBytesF(b)
+			if len(packed) != 1+(n+g.PackedBytesPerElement-1)/g.PackedBytesPerElement {
+				t.Fatalf("%d bytes packed into %d elements", n, len(packed))
+			}
+			key := fmt.Sprint(packed)
+			if prev, ok := seen[key]; ok && prev != string(b) {
+				t.Fatalf("%x and %x packed into the same elements", prev, b)
+			}
+			seen[key] = string(b)
+
+			unpacked, err := g.UnpackBytesF(packed)
+			if err != nil || !bytes.Equal(unpacked, b) {
+				t.Fatalf("Expected %x, got %x (%v)", b, unpacked, err)
+			}
+			unpacked, err = g.UnpackBytes(g.PackBytes(b))
+			if err != nil || !bytes.Equal(unpacked, b) {
+				t.Fatalf("Expected %x, got %x (%v)", b, unpacked, err)
+			}
+		}
+	}
+
+	invalid := [][]g.GoldilocksField{
+		{},
+		{1},             // missing chunk
+		{7, 1, 1},       // extra chunk
+		{1, 0x100},      // non-zero padding
+		{8, 0, 1 << 56}, // chunk wider than 7 bytes
+		{g.GoldilocksField(g.ORDER - 1)},
+	}
+	for _, elems := range invalid {
+		if _, err := g.UnpackBytesF(elems); err == nil {
+			t.Fatalf("Expected %v to be rejected", elems)
+		}
+	}
+}
+
+func TestMarshalF(t *testing.T) {
+	f := g.GoldilocksField(rand.Uint64N(g.ORDER))
+
+	bin, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	var fromBin g.GoldilocksField
+	if err := fromBin.UnmarshalBinary(bin); err != nil || fromBin != f {
+		t.Fatalf("Binary round trip failed: %d %v", fromBin, err)
+	}
+
+	type wrapper struct {
+		F g.GoldilocksField
+		P *g.GoldilocksField
+	}
+	in := wrapper{F: f, P: &f}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	var out wrapper
+	if err := json.Unmarshal(data, &out); err != nil || out.F != f || out.P == nil || *out.P != f {
+		t.Fatalf("JSON round trip of %s failed: %v", data, err)
+	}
+
+	var buf bytes.Buffer
+	var fromGob g.GoldilocksField
+	if err := gob.NewEncoder(&buf).Encode(f); err != nil {
+		t.Fatalf("gob encoding failed: %v", err)
+	}
+	if err := gob.NewDecoder(&buf).Decode(&fromGob); err != nil || fromGob != f {
+		t.Fatalf("gob round trip failed: %d %v", fromGob, err)
+	}
+
+	// Non-canonical values are not accepted, even though MarshalBinary of the
+	// same element never produces them.
+	nonCanonical := g.GoldilocksField(g.ORDER + 3)
+	if text, _ := nonCanonical.MarshalText(); string(text) != "0300000000000000" {
+		t.Fatalf("Expected non-canonical value to marshal canonically, got %s", text)
+	}
+	for _, bad := range []string{`"0100000000000000"x`, `"01000000ffffffff"`, `"0300000000000000ff"`, `"03000000000000"`, `"0A00000000000000"`, `3`} {
+		var z g.GoldilocksField
+		if err := json.Unmarshal([]byte(bad), &z); err == nil {
+			t.Fatalf("Expected %s to be rejected", bad)
+		}
+	}
+}
+
+func TestMarshalgFp5(t *testing.T) {
+	e := gFp5.Sample()
+
+	text, err := e.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText failed: %v", err)
+	}
+	var fromText gFp5.Element
+	if err := fromText.UnmarshalText(text); err != nil || !gFp5.Equals(fromText, e) {
+		t.Fatalf("Text round trip failed: %v", err)
+	}
+
+	data, err := json.Marshal(map[string]gFp5.Element{"e": e})
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	var out map[string]gFp5.Element
+	if err := json.Unmarshal(data, &out); err != nil || !gFp5.Equals(out["e"], e) {
+		t.Fatalf("JSON round trip of %s failed: %v", data, err)
+	}
+
+	bin, _ := e.MarshalBinary()
+	binary.LittleEndian.PutUint64(bin[16:], g.ORDER)
+	var z gFp5.Element
+	if err := z.UnmarshalBinary(bin); err == nil {
+		t.Fatalf("Expected non-canonical limb to be rejected")
+	}
+	if err := z.UnmarshalBinary(bin[:39]); err == nil {
+		t.Fatalf("Expected 39 bytes to be rejected")
+	}
+}
+
 // Goldilocks field tests
 
 // Inputs that covers several input ranges
@@ -228,6 +558,440 @@ func TestAddFDoubleWraparound(t *testing.T) {
 	}
 }
 
+func TestInverseF(t *testing.T) {
+	for _, x := range inputs {
+		inv := g.InverseF(g.GoldilocksField(x)).ToCanonicalUint64()
+
+		elem := g.FromUint64(x % g.ORDER)
+		var expected g.Element
+		expected.Inverse(&elem)
+		if inv != expected.Uint64() {
+			t.Fatalf("Expected Inverse(%d) = %d, but got %d", x, expected.Uint64(), inv)
+		}
+
+		if x%g.ORDER != 0 {
+			one := g.MulF(g.GoldilocksField(x), g.InverseF(g.GoldilocksField(x)))
+			if one.ToCanonicalUint64() != 1 {
+				t.Fatalf("Expected %d * Inverse(%d) = 1, but got %d", x, x, one)
+			}
+		}
+	}
+}
+
+func TestExpF(t *testing.T) {
+	powers := []uint64{0, 1, 2, 3, 7, 1 << 32, g.ORDER - 2, g.ORDER - 1, math.MaxUint64}
+	for _, x := range inputs {
+		for _, power := range powers {
+			res := g.ExpF(g.GoldilocksField(x), power).ToCanonicalUint64()
+
+			elem := g.FromUint64(x % g.ORDER)
+			var expected g.Element
+			expected.Exp(elem, NewBigInt(power))
+			if res != expected.Uint64() {
+				t.Fatalf("Expected %d^%d = %d, but got %d", x, power, expected.Uint64(), res)
+			}
+		}
+	}
+}
+
+func TestLegendreF(t *testing.T) {
+	for _, x := range inputs {
+		res := g.LegendreF(g.GoldilocksField(x))
+
+		elem := g.FromUint64(x % g.ORDER)
+		var expected g.GoldilocksField
+		switch elem.Legendre() {
+		case 1:
+			expected = g.OneF()
+		case -1:
+			expected = g.NegOneF()
+		}
+		if res != expected {
+			t.Fatalf("Expected Legendre(%d) = %d, but got %d", x, expected, res)
+		}
+	}
+}
+
+func TestSqrtF(t *testing.T) {
+	values := append([]uint64{}, inputs...)
+	for i := 0; i < 64; i++ {
+		values = append(values, rand.Uint64N(g.ORDER))
+	}
+
+	for _, x := range values {
+		root, exists := g.SqrtF(g.GoldilocksField(x))
+
+		elem := g.FromUint64(x % g.ORDER)
+		expectedExists := g.Sqrt(&elem) != nil
+		if exists != expectedExists {
+			t.Fatalf("Expected Sqrt(%d) existence to be %v, but got %v", x, expectedExists, exists)
+		}
+		if exists && g.SquareF(root).ToCanonicalUint64() != x%g.ORDER {
+			t.Fatalf("Expected Sqrt(%d)^2 = %d, but got %d", x, x%g.ORDER, g.SquareF(root).ToCanonicalUint64())
+		}
+
+		square := g.SquareF(g.GoldilocksField(x))
+		root, exists = g.SqrtF(square)
+		if !exists || !g.EqualsF(g.SquareF(root), square) {
+			t.Fatalf("Expected Sqrt(%d^2) to exist", x)
+		}
+	}
+}
+
+func TestPowerOfTwoGenerator(t *testing.T) {
+	gen := g.ExpPowerOf2(g.POWER_OF_TWO_GENERATOR, g.TWO_ADICITY-1)
+	if !g.EqualsF(gen, g.NegOneF()) {
+		t.Fatalf("Expected generator^(2^31) to be -1, but got %d", gen)
+	}
+	if !g.EqualsF(g.SquareF(gen), g.OneF()) {
+		t.Fatalf("Expected generator^(2^32) to be 1")
+	}
+}
+
+func TestBatchInverse(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, n := range []int{0, 1, 2, 9, 1 << 14} {
+		in := g.RandArray(n)
+		inF := make([]g.GoldilocksField, n)
+		for i := range in {
+			if i%7 == 2 {
+				in[i] = g.Zero()
+			}
+			inF[i] = g.GoldilocksField(in[i].Uint64())
+		}
+
+		res := g.BatchInverse(in)
+		resF := g.BatchInverseF(inF)
+		if len(res) != n || len(resF) != n {
+			t.Fatalf("Expected %d inverses, but got %d and %d", n, len(res), len(resF))
+		}
+
+		for i := range in {
+			var expected g.Element
+			expected.Inverse(&in[i])
+			if !res[i].Equal(&expected) {
+				t.Fatalf("Expected inverse at index %d to be %v, but got %v", i, expected, res[i])
+			}
+			if resF[i].ToCanonicalUint64() != expected.Uint64() {
+				t.Fatalf("Expected inverse at index %d to be %d, but got %d", i, expected.Uint64(), resF[i])
+			}
+		}
+	}
+}
+
+func randArrayF(n int) []g.GoldilocksField {
+	res := make([]g.GoldilocksField, n)
+	for i := range res {
+		res[i] = g.GoldilocksField(rand.Uint64N(g.ORDER))
+	}
+	return res
+}
+
+func evalPolyF(coeffs []g.GoldilocksField, x g.GoldilocksField) g.GoldilocksField {
+	res := g.ZeroF()
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res = g.AddF(g.MulF(res, x), coeffs[i])
+	}
+	return res
+}
+
+func TestNTT(t *testing.T) {
+	for logN := 0; logN <= 8; logN++ {
+		n := 1 << logN
+		coeffs := randArrayF(n)
+		evals := g.NTT(coeffs)
+
+		for i, x := range g.TwoAdicSubgroupF(logN) {
+			if expected := evalPolyF(coeffs, x); !g.EqualsF(evals[i], expected) {
+				t.Fatalf("NTT of size %d: expected evaluation %d to be %d, but got %d", n, i, expected, evals[i])
+			}
+		}
+
+		interpolated := g.INTT(evals)
+		for i := range coeffs {
+			if !g.EqualsF(interpolated[i], coeffs[i]) {
+				t.Fatalf("INTT of size %d: expected coefficient %d to be %d, but got %d", n, i, coeffs[i], interpolated[i])
+			}
+		}
+	}
+}
+
+func BenchmarkNTT(b *testing.B) {
+	coeffs := randArrayF(1 << 14)
+	for i := 0; i < b.N; i++ {
+		g.NTT(coeffs)
+	}
+}
+
+func TestCosetNTT(t *testing.T) {
+	shift := g.MULTIPLICATIVE_GROUP_GENERATOR
+	for logN := 0; logN <= 6; logN++ {
+		n := 1 << logN
+		coeffs := randArrayF(n)
+		evals := g.CosetNTT(coeffs, shift)
+
+		for i, x := range g.TwoAdicSubgroupF(logN) {
+			if expected := evalPolyF(coeffs, g.MulF(shift, x)); !g.EqualsF(evals[i], expected) {
+				t.Fatalf("Coset NTT of size %d: expected evaluation %d to be %d, but got %d", n, i, expected, evals[i])
+			}
+		}
+
+		interpolated := g.CosetINTT(evals, shift)
+		for i := range coeffs {
+			if !g.EqualsF(interpolated[i], coeffs[i]) {
+				t.Fatalf("Coset INTT of size %d: expected coefficient %d to be %d, but got %d", n, i, coeffs[i], interpolated[i])
+			}
+		}
+	}
+}
+
+func TestLowDegreeExtension(t *testing.T) {
+	const logN, rateBits = 5, 3
+	coeffs := randArrayF(1 << logN)
+	lde := g.LowDegreeExtension(g.NTT(coeffs), rateBits)
+	if len(lde) != 1<<(logN+rateBits) {
+		t.Fatalf("Expected %d evaluations, but got %d", 1<<(logN+rateBits), len(lde))
+	}
+
+	for i, x := range g.TwoAdicSubgroupF(logN + rateBits) {
+		expected := evalPolyF(coeffs, g.MulF(g.MULTIPLICATIVE_GROUP_GENERATOR, x))
+		if !g.EqualsF(lde[i], expected) {
+			t.Fatalf("Expected LDE evaluation %d to be %d, but got %d", i, expected, lde[i])
+		}
+	}
+}
+
+func TestPrimitiveRootOfUnityF(t *testing.T) {
+	for logN := 1; logN <= g.TWO_ADICITY; logN++ {
+		root := g.PrimitiveRootOfUnityF(logN)
+		if !g.EqualsF(g.ExpPowerOf2(root, uint(logN-1)), g.NegOneF()) {
+			t.Fatalf("Expected 2^%d-th root of unity to have order exactly 2^%d", logN, logN)
+		}
+	}
+}
+
+func TestVecOps(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 4, 7, 8, 15, 16, 33, 100} {
+		a, b := randArrayF(n), randArrayF(n)
+		c := randArrayF(1)[0]
+		dst := make([]g.GoldilocksField, n)
+
+		g.AddVec(dst, a, b)
+		for i := range dst {
+			if !g.EqualsF(dst[i], g.AddF(a[i], b[i])) {
+				t.Fatalf("AddVec n=%d: mismatch at index %d", n, i)
+			}
+		}
+
+		g.SubVec(dst, a, b)
+		for i := range dst {
+			if !g.EqualsF(dst[i], g.SubF(a[i], b[i])) {
+				t.Fatalf("SubVec n=%d: mismatch at index %d", n, i)
+			}
+		}
+
+		g.MulVec(dst, a, b)
+		for i := range dst {
+			if !g.EqualsF(dst[i], g.MulF(a[i], b[i])) {
+				t.Fatalf("MulVec n=%d: mismatch at index %d", n, i)
+			}
+		}
+
+		g.ScalarMulVec(dst, a, c)
+		for i := range dst {
+			if !g.EqualsF(dst[i], g.MulF(a[i], c)) {
+				t.Fatalf("ScalarMulVec n=%d: mismatch at index %d", n, i)
+			}
+		}
+
+		copy(dst, b)
+		g.AxpyVec(dst, c, a)
+		for i := range dst {
+			if !g.EqualsF(dst[i], g.AddF(b[i], g.MulF(c, a[i]))) {
+				t.Fatalf("AxpyVec n=%d: mismatch at index %d", n, i)
+			}
+		}
+
+		expected := g.ZeroF()
+		for i := range a {
+			expected = g.AddF(expected, g.MulF(a[i], b[i]))
+		}
+		if ip := g.InnerProduct(a, b); !g.EqualsF(ip, expected) {
+			t.Fatalf("InnerProduct n=%d: expected %d, got %d", n, expected, ip)
+		}
+	}
+}
+
+func TestLinearCombination(t *testing.T) {
+	const n, k = 21, 5
+	coeffs := randArrayF(k)
+	vecs := make([][]g.GoldilocksField, k)
+	for j := range vecs {
+		vecs[j] = randArrayF(n)
+	}
+
+	dst := make([]g.GoldilocksField, n)
+	g.LinearCombination(dst, coeffs, vecs)
+	for i := range dst {
+		expected := g.ZeroF()
+		for j := range vecs {
+			expected = g.AddF(expected, g.MulF(coeffs[j], vecs[j][i]))
+		}
+		if !g.EqualsF(dst[i], expected) {
+			t.Fatalf("Expected linear combination at index %d to be %d, but got %d", i, expected, dst[i])
+		}
+	}
+
+	// dst may be one of the combined vectors.
+	expected := append([]g.GoldilocksField{}, dst...)
+	g.LinearCombination(vecs[0], coeffs, vecs)
+	for i := range dst {
+		if !g.EqualsF(vecs[0][i], expected[i]) {
+			t.Fatalf("Aliased linear combination mismatch at index %d", i)
+		}
+	}
+}
+
+func TestAccumulators(t *testing.T) {
+	// Sums of products of the edge case inputs, including non-canonical values
+	// and enough terms to carry into the top word, against big.Int.
+	for _, n := range []int{0, 1, 5, 100, 1000} {
+		want := new(big.Int)
+		var accF g.AccF
+		var acc g.Acc
+		for i := 0; i < n; i++ {
+			x, y := inputs[i%len(inputs)], inputs[(7*i+3)%len(inputs)]
+			if i%3 == 0 {
+				x = math.MaxUint64 - uint64(i)
+			}
+			accF.MulAdd(g.GoldilocksField(x), g.GoldilocksField(y))
+			accF.Add(g.GoldilocksField(y))
+			xe, ye := g.FromUint64(x), g.FromUint64(y)
+			acc.MulAdd(&xe, &ye)
+			acc.Add(&ye)
+
+			term := new(big.Int).Mul(NewBigInt(x), NewBigInt(y))
+			want.Add(want, term.Add(term, NewBigInt(y)))
+		}
+		want.Mod(want, NewBigInt(g.ORDER))
+
+		if got := accF.Reduce().ToCanonicalUint64(); got != want.Uint64() {
+			t.Fatalf("AccF with %d terms: got %d, want %d", n, got, want)
+		}
+		got := acc.Reduce()
+		if got.Uint64() != want.Uint64() || got[0] >= g.ORDER {
+			t.Fatalf("Acc with %d terms: got %d, want %d", n, got.Uint64(), want)
+		}
+	}
+
+	for _, hi := range inputs {
+		for _, lo := range inputs {
+			want := new(big.Int).Lsh(NewBigInt(hi), 64)
+			want.Add(want, NewBigInt(lo)).Mod(want, NewBigInt(g.ORDER))
+			if got := g.Reduce128F(hi, lo).ToCanonicalUint64(); got != want.Uint64() {
+				t.Fatalf("Reduce128F(%d, %d) = %d, want %d", hi, lo, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkInnerProduct(b *testing.B) {
+	const n = 1024
+	x, y := make([]g.GoldilocksField, n), make([]g.GoldilocksField, n)
+	for i := range x {
+		x[i], y[i] = g.SampleF(), g.SampleF()
+	}
+	// The vector kernels where available, the lazy accumulator with purego.
+	b.Run("InnerProduct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g.InnerProduct(x, y)
+		}
+	})
+	b.Run("Reduced", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res := g.ZeroF()
+			for j := range x {
+				res = g.AddF(res, g.MulF(x[j], y[j]))
+			}
+		}
+	})
+}
+
+func TestVecLengthMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected AddVec to panic on slices of different lengths")
+		}
+	}()
+	g.AddVec(make([]g.GoldilocksField, 4), randArrayF(4), randArrayF(5))
+}
+
+// Both representations of the Goldilocks field implement g.Field with the same
+// arithmetic.
+func TestFieldInterface(t *testing.T) {
+	t.Run("Element", testFieldInterface[g.Element])
+	t.Run("GoldilocksField", testFieldInterface[g.GoldilocksField])
+}
+
+func testFieldInterface[F any, PF g.Field[F]](t *testing.T) {
+	for _, lhs := range inputs {
+		for _, rhs := range inputs {
+			var a, b, z F
+			PF(&a).SetUint64(lhs)
+			PF(&b).SetUint64(rhs)
+
+			PF(&z).Add(&a, &b)
+			if res := PF(&z).Uint64(); res != SumMod(lhs, rhs) {
+				t.Fatalf("Expected %d + %d = %d, but got %d", lhs, rhs, SumMod(lhs, rhs), res)
+			}
+			PF(&z).Sub(&a, &b)
+			if res := PF(&z).Uint64(); res != SubMod(lhs, rhs) {
+				t.Fatalf("Expected %d - %d = %d, but got %d", lhs, rhs, SubMod(lhs, rhs), res)
+			}
+			PF(&z).Mul(&a, &b)
+			if res := PF(&z).Uint64(); res != MulMod(lhs, rhs) {
+				t.Fatalf("Expected %d * %d = %d, but got %d", lhs, rhs, MulMod(lhs, rhs), res)
+			}
+			if PF(&a).Equal(&b) != (SubMod(lhs, rhs) == 0) {
+				t.Fatalf("Equal(%d, %d) is wrong", lhs, rhs)
+			}
+		}
+
+		var a, z F
+		PF(&a).SetUint64(lhs)
+		PF(&z).Square(&a)
+		if res := PF(&z).Uint64(); res != SquareMod(lhs) {
+			t.Fatalf("Expected %d^2 = %d, but got %d", lhs, SquareMod(lhs), res)
+		}
+		PF(&z).Double(&a)
+		if res := PF(&z).Uint64(); res != SumMod(lhs, lhs) {
+			t.Fatalf("Expected 2*%d = %d, but got %d", lhs, SumMod(lhs, lhs), res)
+		}
+		PF(&z).Neg(&a)
+		if res := PF(&z).Uint64(); res != NegMod(lhs) {
+			t.Fatalf("Expected Neg(%d) = %d, but got %d", lhs, NegMod(lhs), res)
+		}
+		PF(&z).Set(&a)
+		if PF(&z).Uint64() != PF(&a).Uint64() {
+			t.Fatalf("Set(%d) is wrong", lhs)
+		}
+		if PF(&a).IsZero() != (PF(&a).Uint64() == 0) {
+			t.Fatalf("IsZero(%d) is wrong", lhs)
+		}
+	}
+
+	var z F
+	PF(&z).SetOne()
+	if PF(&z).Uint64() != 1 {
+		t.Fatalf("SetOne is wrong")
+	}
+	PF(&z).SetZero()
+	if !PF(&z).IsZero() {
+		t.Fatalf("SetZero is wrong")
+	}
+}
+
 // Quintic extension tests
 
 func TestQuinticExtensionAddSubMulSquare(t *testing.T) {
@@ -285,6 +1049,93 @@ func TestQuinticExtensionAddSubMulSquare(t *testing.T) {
 	}
 }
 
+// The in-place API matches the value functions, also when operands alias. Mul
+// and Square are checked against the independent constant-time versions.
+func TestQuinticExtensionInPlace(t *testing.T) {
+	negOne := g.NegOne()
+	edge := []gFp5.Element{
+		gFp5.FP5_ZERO,
+		gFp5.FP5_ONE,
+		{*negOne, *negOne, *negOne, *negOne, *negOne},
+		{g.Zero(), g.Zero(), g.Zero(), g.Zero(), *negOne},
+	}
+	elems := append(edge, gFp5.Sample(), gFp5.Sample(), gFp5.Sample(), gFp5.Sample())
+
+	check := func(name string, got *gFp5.Element, want gFp5.Element) {
+		t.Helper()
+		if !gFp5.Equals(*got, want) {
+			t.Fatalf("%s: expected %v, got %v", name, want.ToUint64Array(), got.ToUint64Array())
+		}
+	}
+
+	for _, x := range elems {
+		for _, y := range elems {
+			var z gFp5.Element
+			check("Mul", z.Mul(&x, &y), gFp5.MulCT(x, y))
+			check("Add", z.Add(&x, &y), gFp5.Add(x, y))
+			check("Sub", z.Sub(&x, &y), gFp5.Sub(x, y))
+			check("MulByBase", z.MulByBase(&x, &y[0]), gFp5.ScalarMul(x, y[0]))
+
+			z = x
+			check("Mul aliasing", z.Mul(&z, &y), gFp5.Mul(x, y))
+			z = y
+			check("Mul aliasing", z.Mul(&x, &z), gFp5.Mul(x, y))
+		}
+
+		var z gFp5.Element
+		check("Square", z.Square(&x), gFp5.SquareCT(x))
+		check("Double", z.Double(&x), gFp5.Double(x))
+		check("Neg", z.Neg(&x), gFp5.Neg(x))
+		check("MulByX", z.MulByX(&x), gFp5.Mul(x, gFp5.Element{g.Zero(), g.One()}))
+
+		z = x
+		check("Square aliasing", z.Square(&z), gFp5.Square(x))
+		if z.Set(&x); !z.Equal(&x) || z.IsZero() != gFp5.IsZero(x) {
+			t.Fatalf("Set, Equal or IsZero is wrong for %v", x.ToUint64Array())
+		}
+	}
+
+	var z gFp5.Element
+	check("SetOne", z.SetOne(), gFp5.FP5_ONE)
+	check("SetZero", z.SetZero(), gFp5.FP5_ZERO)
+}
+
+func BenchmarkMulgFp5(b *testing.B) {
+	x, y := gFp5.Sample(), gFp5.Sample()
+	for i := 0; i < b.N; i++ {
+		x = gFp5.Mul(x, y)
+	}
+}
+
+func BenchmarkMulgFp5InPlace(b *testing.B) {
+	x, y := gFp5.Sample(), gFp5.Sample()
+	for i := 0; i < b.N; i++ {
+		x.Mul(&x, &y)
+	}
+}
+
+// The constant-time product, for comparison with Mul.
+func BenchmarkMulCTgFp5(b *testing.B) {
+	x, y := gFp5.Sample(), gFp5.Sample()
+	for i := 0; i < b.N; i++ {
+		x = gFp5.MulCT(x, y)
+	}
+}
+
+func BenchmarkSquaregFp5(b *testing.B) {
+	x := gFp5.Sample()
+	for i := 0; i < b.N; i++ {
+		x = gFp5.Square(x)
+	}
+}
+
+func BenchmarkSquaregFp5InPlace(b *testing.B) {
+	x := gFp5.Sample()
+	for i := 0; i < b.N; i++ {
+		x.Square(&x)
+	}
+}
+
 func TestQuinticExtensionAddSubMulSquareF(t *testing.T) {
 	val1 := gFp5.FromPlonky2GoldilocksField([]g.GoldilocksField{
 		g.GoldilocksField(0x1234567890ABCDEF),
@@ -391,6 +1242,30 @@ func TestTryInverse(t *testing.T) {
 	}
 }
 
+func TestBatchInverseQuinticExtension(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, n := range []int{0, 1, 5, 1 << 12} {
+		in := make([]gFp5.Element, n)
+		for i := range in {
+			if i%6 != 1 {
+				in[i] = gFp5.Sample()
+			}
+		}
+
+		res := gFp5.BatchInverse(in)
+		if len(res) != n {
+			t.Fatalf("Expected %d inverses, but got %d", n, len(res))
+		}
+		for i := range in {
+			expected := gFp5.InverseOrZero(in[i])
+			if !gFp5.Equals(res[i], expected) {
+				t.Fatalf("Expected inverse at index %d to be %v, but got %v", i, expected, res[i])
+			}
+		}
+	}
+}
+
 func TestQuinticExtSgn0(t *testing.T) {
 	if !gFp5.Sgn0(gFp5.Element{
 		g.FromUint64(7146494650688613286),
@@ -497,3 +1372,188 @@ func TestLegendre(t *testing.T) {
 		t.Fatalf("Expected Legendre symbol of zero to be zero")
 	}
 }
+
+func TestConstantTimeQuinticExtension(t *testing.T) {
+	const allOnes = uint64(0xFFFFFFFFFFFFFFFF)
+
+	edge := gFp5.Element{g.FromUint64(g.ORDER - 1), g.FromUint64(1), g.Zero(), g.FromUint64(g.EPSILON), g.FromUint64(g.ORDER - 2)}
+	values := []gFp5.Element{gFp5.FP5_ZERO, gFp5.FP5_ONE, gFp5.Neg(gFp5.FP5_ONE), edge}
+	for i := 0; i < 32; i++ {
+		x := gFp5.Sample()
+		values = append(values, x, gFp5.Square(x))
+	}
+
+	for _, a := range values {
+		b := gFp5.Sample()
+
+		if !gFp5.Equals(gFp5.AddCT(a, b), gFp5.Add(a, b)) ||
+			!gFp5.Equals(gFp5.SubCT(a, b), gFp5.Sub(a, b)) ||
+			!gFp5.Equals(gFp5.NegCT(a), gFp5.Neg(a)) ||
+			!gFp5.Equals(gFp5.MulCT(a, b), gFp5.Mul(a, b)) ||
+			!gFp5.Equals(gFp5.SquareCT(a), gFp5.Square(a)) ||
+			!gFp5.Equals(gFp5.RepeatedFrobeniusCT(a, 3), gFp5.RepeatedFrobenius(a, 3)) {
+			t.Fatalf("Arithmetic mismatch for %s", a.ToString())
+		}
+
+		inv, ok := gFp5.InverseOrZeroCT(a)
+		if !gFp5.Equals(inv, gFp5.InverseOrZero(a)) || (ok == allOnes) == gFp5.IsZero(a) {
+			t.Fatalf("InverseOrZeroCT mismatch for %s", a.ToString())
+		}
+		if (gFp5.IsZeroCT(a) == allOnes) != gFp5.IsZero(a) {
+			t.Fatalf("IsZeroCT mismatch for %s", a.ToString())
+		}
+
+		quo, ok := gFp5.DivCT(b, a)
+		if gFp5.IsZero(a) {
+			if ok != 0 || !gFp5.IsZero(quo) {
+				t.Fatalf("Expected DivCT by zero to fail")
+			}
+		} else if ok != allOnes || !gFp5.Equals(quo, gFp5.Div(b, a)) {
+			t.Fatalf("DivCT mismatch for %s", a.ToString())
+		}
+
+		legendre, legendreCT := gFp5.Legendre(a), gFp5.LegendreCT(a)
+		if !legendre.Equal(&legendreCT) {
+			t.Fatalf("LegendreCT mismatch for %s", a.ToString())
+		}
+
+		if (gFp5.Sgn0CT(a) == allOnes) != gFp5.Sgn0(a) {
+			t.Fatalf("Sgn0CT mismatch for %s", a.ToString())
+		}
+
+		root, exists := gFp5.CanonicalSqrt(a)
+		rootCT, ok := gFp5.CanonicalSqrtCT(a)
+		if exists != (ok == allOnes) || !gFp5.Equals(root, rootCT) {
+			t.Fatalf("CanonicalSqrtCT mismatch for %s", a.ToString())
+		}
+		rootCT, ok = gFp5.SqrtCT(a)
+		if exists != (ok == allOnes) {
+			t.Fatalf("SqrtCT existence mismatch for %s", a.ToString())
+		}
+		if exists && !gFp5.Equals(gFp5.Square(rootCT), a) {
+			t.Fatalf("SqrtCT returned a wrong root for %s", a.ToString())
+		}
+		if !exists && !gFp5.IsZero(rootCT) {
+			t.Fatalf("Expected SqrtCT of a non-square to be zero")
+		}
+
+		if !gFp5.Equals(gFp5.Select(0, a, b), a) || !gFp5.Equals(gFp5.Select(allOnes, a, b), b) {
+			t.Fatalf("Select mismatch")
+		}
+		if gFp5.EqualsCT(a, a) != allOnes || (gFp5.EqualsCT(a, b) == allOnes) != gFp5.Equals(a, b) {
+			t.Fatalf("EqualsCT mismatch")
+		}
+	}
+}
+
+func TestExpNormTraceQuinticExtension(t *testing.T) {
+	x := gFp5.Sample()
+
+	expected := gFp5.FP5_ONE
+	for i := 0; i < 13; i++ {
+		expected = gFp5.Mul(expected, x)
+	}
+	if !gFp5.Equals(gFp5.ExpUint64(x, 13), expected) {
+		t.Fatalf("Expected x^13 to match repeated multiplication")
+	}
+	if !gFp5.Equals(gFp5.ExpUint64(x, 0), gFp5.FP5_ONE) {
+		t.Fatalf("Expected x^0 = 1")
+	}
+
+	// Negative powers are powers of the inverse.
+	if !gFp5.Equals(gFp5.Exp(x, big.NewInt(-1)), gFp5.InverseOrZero(x)) {
+		t.Fatalf("Expected x^-1 to be the inverse of x")
+	}
+	if !gFp5.Equals(gFp5.Mul(gFp5.Exp(x, big.NewInt(-13)), expected), gFp5.FP5_ONE) {
+		t.Fatalf("Expected x^-13 * x^13 = 1")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("Expected 0^-1 to panic")
+			}
+		}()
+		gFp5.Exp(gFp5.FP5_ZERO, big.NewInt(-1))
+	}()
+
+	// x^(p^5 - 1) = 1 and x^p is the Frobenius map.
+	p := new(big.Int).SetUint64(g.ORDER)
+	order := new(big.Int).Exp(p, big.NewInt(5), nil)
+	if !gFp5.Equals(gFp5.Exp(x, order.Sub(order, big.NewInt(1))), gFp5.FP5_ONE) {
+		t.Fatalf("Expected x^(p^5 - 1) = 1")
+	}
+	if !gFp5.Equals(gFp5.Exp(x, p), gFp5.Frobenius(x)) {
+		t.Fatalf("Expected x^p = Frobenius(x)")
+	}
+
+	// Norm and trace are the product and the sum of the conjugates.
+	product, sum := gFp5.FP5_ONE, gFp5.FP5_ZERO
+	for k := 0; k < 5; k++ {
+		conjugate := gFp5.RepeatedFrobenius(x, k)
+		product = gFp5.Mul(product, conjugate)
+		sum = gFp5.Add(sum, conjugate)
+	}
+	if !gFp5.IsInBaseField(product) || !gFp5.IsInBaseField(sum) {
+		t.Fatalf("Expected norm and trace to lie in the base field")
+	}
+	norm, trace := gFp5.Norm(x), gFp5.Trace(x)
+	if !norm.Equal(&product[0]) || !trace.Equal(&sum[0]) {
+		t.Fatalf("Norm or trace mismatch")
+	}
+
+	// The norm is multiplicative and the trace additive.
+	y := gFp5.Sample()
+	normXY := gFp5.Norm(gFp5.Mul(x, y))
+	normProduct := gFp5.Norm(x)
+	normY := gFp5.Norm(y)
+	normProduct.Mul(&normProduct, &normY)
+	if !normXY.Equal(&normProduct) {
+		t.Fatalf("Expected Norm(xy) = Norm(x) Norm(y)")
+	}
+	traceXY := gFp5.Trace(gFp5.Add(x, y))
+	traceSum := gFp5.Trace(x)
+	traceY := gFp5.Trace(y)
+	traceSum.Add(&traceSum, &traceY)
+	if !traceXY.Equal(&traceSum) {
+		t.Fatalf("Expected Trace(x+y) = Trace(x) + Trace(y)")
+	}
+
+	if gFp5.IsInBaseField(x) || !gFp5.IsInBaseField(gFp5.FromUint64(42)) {
+		t.Fatalf("IsInBaseField mismatch")
+	}
+}
+
+func TestMinimalPolynomialQuinticExtension(t *testing.T) {
+	evalAt := func(coeffs []g.Element, x gFp5.Element) gFp5.Element {
+		res := gFp5.FP5_ZERO
+		for i := len(coeffs) - 1; i >= 0; i-- {
+			res = gFp5.Add(gFp5.Mul(res, x), gFp5.FromF(coeffs[i]))
+		}
+		return res
+	}
+
+	x := gFp5.Sample()
+	minPoly := gFp5.MinimalPolynomial(x)
+	if len(minPoly) != 6 || !minPoly[5].IsOne() {
+		t.Fatalf("Expected a monic polynomial of degree 5, got %d coefficients", len(minPoly))
+	}
+	for k := 0; k < 5; k++ {
+		if !gFp5.IsZero(evalAt(minPoly, gFp5.RepeatedFrobenius(x, k))) {
+			t.Fatalf("Expected conjugate %d to be a root of the minimal polynomial", k)
+		}
+	}
+
+	// The constant term is -Norm(x) and the X^4 coefficient -Trace(x).
+	negNorm, negTrace := gFp5.Norm(x), gFp5.Trace(x)
+	negNorm.Neg(&negNorm)
+	negTrace.Neg(&negTrace)
+	if !minPoly[0].Equal(&negNorm) || !minPoly[4].Equal(&negTrace) {
+		t.Fatalf("Minimal polynomial coefficients do not match norm and trace")
+	}
+
+	base := gFp5.FromUint64(7)
+	minPoly = gFp5.MinimalPolynomial(base)
+	if len(minPoly) != 2 || !gFp5.IsZero(evalAt(minPoly, base)) {
+		t.Fatalf("Expected minimal polynomial of a base field element to be X - a")
+	}
+}
@@ -0,0 +1,97 @@
+package goldilocks
+
+import "math/bits"
+
+// Lazy-reduction accumulators for sums of products. Products are added as
+// 128-bit integers, with a third word counting the carries out of the top, and
+// the sum is reduced once at the end instead of after every MulF and AddF.
+// An accumulator holds at most 2^32 products or values.
+//
+// AccF works on GoldilocksField values, which may be non-canonical. Acc works
+// on the Montgomery form of Element: Reduce divides the sum by R = 2^64, so
+// MulAdd(x, y) contributes x*y, and Add(x) stores x*R to contribute x.
+
+// Chunk size for long sums, well within the capacity of an accumulator.
+const accMaxTerms = 1 << 30
+
+// -ORDER^-1 mod 2^64, for Montgomery reductions.
+const qInvNeg = uint64(18446744069414584319)
+
+// Returns hi*2^64 + lo mod ORDER, not necessarily canonical.
+func Reduce128F(hi, lo uint64) GoldilocksField {
+	// 2^64 = EPSILON and 2^96 = -1 mod ORDER.
+	hiHi := hi >> 32
+	hiLo := hi & EPSILON
+
+	t0, borrow := bits.Sub64(lo, hiHi, 0)
+	t0 -= EPSILON & -borrow
+	t1 := hiLo * EPSILON
+
+	sum, over := bits.Add64(t0, t1, 0)
+	return GoldilocksField(sum + EPSILON*over)
+}
+
+type AccF struct {
+	lo, hi, top uint64
+}
+
+func (a *AccF) Add(x GoldilocksField) {
+	var c uint64
+	a.lo, c = bits.Add64(a.lo, uint64(x), 0)
+	a.hi, c = bits.Add64(a.hi, 0, c)
+	a.top += c
+}
+
+func (a *AccF) MulAdd(x, y GoldilocksField) {
+	hi, lo := bits.Mul64(uint64(x), uint64(y))
+	var c uint64
+	a.lo, c = bits.Add64(a.lo, lo, 0)
+	a.hi, c = bits.Add64(a.hi, hi, c)
+	a.top += c
+}
+
+func (a *AccF) Reduce() GoldilocksField {
+	// Fold top * 2^128 into the high word with 2^64 = EPSILON. top < 2^32, so
+	// a carry leaves a small high word and the second fold cannot overflow.
+	h, c := bits.Add64(a.hi, a.top*EPSILON, 0)
+	h += EPSILON & -c
+	return Reduce128F(h, a.lo)
+}
+
+type Acc struct {
+	lo, hi, top uint64
+}
+
+// Adds x*R, so that x contributes x after the reduction.
+func (a *Acc) Add(x *Element) {
+	var c uint64
+	a.hi, c = bits.Add64(a.hi, x[0], 0)
+	a.top += c
+}
+
+func (a *Acc) MulAdd(x, y *Element) {
+	hi, lo := bits.Mul64(x[0], y[0])
+	var c uint64
+	a.lo, c = bits.Add64(a.lo, lo, 0)
+	a.hi, c = bits.Add64(a.hi, hi, c)
+	a.top += c
+}
+
+// Montgomery reduction of the sum, i.e. sum / 2^64 mod ORDER, in canonical
+// form. Runs in constant time.
+func (a *Acc) Reduce() Element {
+	// Fold top * 2^128 + hi * 2^64 into h * 2^64 with h < ORDER.
+	h, c := bits.Add64(a.hi, a.top*EPSILON, 0)
+	h += EPSILON & -c
+	r, borrow := bits.Sub64(h, ORDER, 0)
+	h = r + (ORDER & -borrow)
+
+	// h * 2^64 + lo < ORDER * 2^64, as required by the reduction.
+	m := a.lo * qInvNeg
+	mHi, mLo := bits.Mul64(m, ORDER)
+	_, c = bits.Add64(a.lo, mLo, 0)
+	t, c := bits.Add64(h, mHi, c)
+	r, borrow = bits.Sub64(t, ORDER, 0)
+	keep := -(borrow &^ c)
+	return Element{(t & keep) | (r &^ keep)}
+}
@@ -0,0 +1,9 @@
+//go:build !purego
+
+package goldilocks
+
+import "golang.org/x/sys/cpu"
+
+// The amd64 kernels multiply with MULX, and Pow7Vec interleaves two
+// reductions with ADCX and ADOX.
+var supportArithAsm = cpu.X86.HasBMI2 && cpu.X86.HasADX
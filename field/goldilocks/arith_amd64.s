@@ -0,0 +1,89 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// MUL_F mirrors MulF step by step, replacing its branches with conditional
+// moves of eps, a register holding EPSILON. Masks built with SBBQ t, t would be
+// shorter, but t then carries a false dependency from one multiplication to the
+// next.
+
+// t = carry ? EPSILON : 0, leaving the flags alone.
+#define CARRY_EPS(eps, t) \
+	MOVL    $0, t \
+	CMOVQCS eps, t
+
+// r = DX * s mod ORDER, with MULX. r must not be DX. Clobbers hi, t0 and t1.
+#define MUL_F(s, r, eps, hi, t0, t1) \
+	MULXQ s, r, hi \
+	MOVQ  hi, t0 \
+	SHRQ  $32, t0 \
+	MOVL  hi, hi \
+	SUBQ  t0, r \
+	CARRY_EPS(eps, t1) \
+	SUBQ  t1, r \
+	MOVQ  hi, t0 \
+	SHLQ  $32, t0 \
+	SUBQ  hi, t0 \
+	ADDQ  t0, r \
+	CARRY_EPS(eps, t1) \
+	ADDQ  t1, r
+
+// r1 = DX * s1 and r2 = DX * s2 mod ORDER, two MUL_F whose final additions run
+// side by side on the two carry chains of ADX: ADCX on CF and ADOX on OF.
+// r1 and r2 must not be DX. Clobbers hi1, hi2, t1 and t2.
+#define MUL_F2(s1, s2, r1, r2, eps, hi1, hi2, t1, t2) \
+	MULXQ   s1, r1, hi1 \
+	MULXQ   s2, r2, hi2 \
+	MOVQ    hi1, t1 \
+	SHRQ    $32, t1 \
+	MOVL    hi1, hi1 \
+	SUBQ    t1, r1 \
+	CARRY_EPS(eps, t1) \
+	SUBQ    t1, r1 \
+	MOVQ    hi2, t2 \
+	SHRQ    $32, t2 \
+	MOVL    hi2, hi2 \
+	SUBQ    t2, r2 \
+	CARRY_EPS(eps, t2) \
+	SUBQ    t2, r2 \
+	MOVQ    hi1, t1 \
+	SHLQ    $32, t1 \
+	SUBQ    hi1, t1 \
+	MOVQ    hi2, t2 \
+	SHLQ    $32, t2 \
+	SUBQ    hi2, t2 \
+	XORL    hi1, hi1 \
+	MOVL    $0, hi2 \
+	ADCXQ   t1, r1 \
+	ADOXQ   t2, r2 \
+	CMOVQCS eps, hi1 \
+	CMOVQOS eps, hi2 \
+	ADDQ    hi1, r1 \
+	ADDQ    hi2, r2
+
+// func pow7VecAsm(dst, a *GoldilocksField, n int)
+TEXT ·pow7VecAsm(SB), NOSPLIT, $0-24
+	MOVQ dst+0(FP), R8
+	MOVQ a+8(FP), R9
+	MOVQ n+16(FP), R10
+	MOVL  $0xffffffff, R14
+	TESTQ R10, R10
+	JZ    done
+
+loop:
+	// x2 = x^2, x3 = x2*x, x4 = x2^2, x7 = x3*x4
+	MOVQ (R9), BX
+	MOVQ BX, DX
+	MUL_F(BX, R11, R14, CX, SI, DI)
+	MOVQ R11, DX
+	MUL_F2(BX, R11, R12, R13, R14, CX, SI, DI, AX)
+	MOVQ R13, DX
+	MUL_F(R12, AX, R14, CX, SI, DI)
+	MOVQ AX, (R8)
+	ADDQ $8, R8
+	ADDQ $8, R9
+	DECQ R10
+	JNZ  loop
+
+done:
+	RET
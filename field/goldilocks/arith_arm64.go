@@ -0,0 +1,5 @@
+//go:build !purego
+
+package goldilocks
+
+const supportArithAsm = true
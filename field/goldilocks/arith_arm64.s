@@ -0,0 +1,46 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// MUL_F mirrors MulF step by step, replacing its branches with masks: CSETM
+// turns the carry into 0 or -1, and a right shift by 32 truncates -1 to
+// EPSILON. Carries of subtractions are inverted on arm64, a borrow clears C.
+
+// r = a * b mod ORDER with MUL and UMULH. r must differ from a and b.
+// Clobbers hi, t0 and t1.
+#define MUL_F(a, b, r, hi, t0, t1) \
+	MUL   b, a, r \
+	UMULH b, a, hi \
+	LSR   $32, hi, t0 \
+	MOVWU hi, hi \
+	SUBS  t0, r, r \
+	CSETM LO, t1 \
+	LSR   $32, t1, t1 \
+	SUB   t1, r, r \
+	LSL   $32, hi, t0 \
+	SUB   hi, t0, t0 \
+	ADDS  t0, r, r \
+	CSETM HS, t1 \
+	LSR   $32, t1, t1 \
+	ADD   t1, r, r
+
+// func pow7VecAsm(dst, a *GoldilocksField, n int)
+TEXT ·pow7VecAsm(SB), NOSPLIT, $0-24
+	MOVD dst+0(FP), R8
+	MOVD a+8(FP), R9
+	MOVD n+16(FP), R10
+	CBZ  R10, done
+
+loop:
+	// x2 = x^2, x3 = x2*x, x4 = x2^2, x7 = x3*x4
+	MOVD.P 8(R9), R0
+	MUL_F(R0, R0, R1, R3, R4, R5)
+	MUL_F(R1, R0, R2, R3, R4, R5)
+	MUL_F(R1, R1, R6, R3, R4, R5)
+	MUL_F(R2, R6, R7, R3, R4, R5)
+	MOVD.P R7, 8(R8)
+	SUB    $1, R10, R10
+	CBNZ   R10, loop
+
+done:
+	RET
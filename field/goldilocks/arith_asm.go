@@ -0,0 +1,21 @@
+//go:build (amd64 || arm64) && !purego
+
+package goldilocks
+
+// Scalar AddF, SubF and MulF stay in Go: they inline, and a call into
+// assembly costs more than their branches. Only whole-slice kernels, where the
+// call is amortized over the elements, are worth a trip to assembly.
+
+// dst[i] = a[i]^7 for n elements, with the same representatives as
+// pow7VecGeneric.
+//
+//go:noescape
+func pow7VecAsm(dst, a *GoldilocksField, n int)
+
+func pow7Vec(dst, a []GoldilocksField) {
+	if supportArithAsm && len(dst) > 0 {
+		pow7VecAsm(&dst[0], &a[0], len(dst))
+		return
+	}
+	pow7VecGeneric(dst, a)
+}
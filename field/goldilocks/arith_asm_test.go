@@ -0,0 +1,65 @@
+//go:build (amd64 || arm64) && !purego
+
+package goldilocks
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// Random values including non-canonical representatives and the edge cases
+// around EPSILON and ORDER.
+func randVecF(n int) []GoldilocksField {
+	edges := []uint64{0, 1, EPSILON - 1, EPSILON, EPSILON + 1, ORDER - 1, ORDER, ORDER + 1, math.MaxUint64 - 1, math.MaxUint64}
+	res := make([]GoldilocksField, n)
+	for i := range res {
+		if rand.IntN(4) == 0 {
+			res[i] = GoldilocksField(edges[rand.IntN(len(edges))])
+		} else {
+			res[i] = GoldilocksField(rand.Uint64())
+		}
+	}
+	return res
+}
+
+func TestPow7VecAsmMatchesGeneric(t *testing.T) {
+	if !supportArithAsm {
+		t.Skip("no assembly kernels on this CPU")
+	}
+	for _, n := range []int{1, 2, 12, 13, 1000} {
+		in := randVecF(n)
+		got, want := make([]GoldilocksField, n), make([]GoldilocksField, n)
+		pow7VecAsm(&got[0], &in[0], n)
+		pow7VecGeneric(want, in)
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("pow7VecAsm(%d) = %d, want %d", in[i], got[i], want[i])
+			}
+		}
+		// In place.
+		pow7VecAsm(&in[0], &in[0], n)
+		for i := range in {
+			if in[i] != want[i] {
+				t.Fatalf("in place pow7VecAsm differs at %d", i)
+			}
+		}
+	}
+}
+
+func BenchmarkPow7Vec(b *testing.B) {
+	for _, n := range []int{1, 12, 1 << 10} {
+		state := randVecF(n)
+		b.Run(fmt.Sprintf("%d/Asm", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				pow7VecAsm(&state[0], &state[0], len(state))
+			}
+		})
+		b.Run(fmt.Sprintf("%d/Generic", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				pow7VecGeneric(state, state)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+//go:build (!amd64 && !arm64) || purego
+
+package goldilocks
+
+func pow7Vec(dst, a []GoldilocksField) {
+	pow7VecGeneric(dst, a)
+}
@@ -0,0 +1,40 @@
+package goldilocks
+
+import "github.com/ppd0705/poseidon_crypto/internal/batch"
+
+// Slices shorter than this are inverted on the calling goroutine.
+const batchInverseMinChunk = 1 << 12
+
+// Inverts every element of the slice using Montgomery's trick. Zero elements are
+// skipped and map to zero in the result.
+func BatchInverse(in []Element) []Element {
+	res := make([]Element, len(in))
+	batch.ParallelChunks(len(in), batchInverseMinChunk, func(start, end int) {
+		batch.InverseChunk(in[start:end], res[start:end],
+			func(e *Element) bool { return e.IsZero() },
+			func(a, b *Element) Element { return Mul(a, b) },
+			func(e *Element) Element {
+				var inv Element
+				inv.Inverse(e)
+				return inv
+			},
+			One(),
+		)
+	})
+	return res
+}
+
+// Inverts every element of the slice using Montgomery's trick. Zero elements are
+// skipped and map to zero in the result.
+func BatchInverseF(in []GoldilocksField) []GoldilocksField {
+	res := make([]GoldilocksField, len(in))
+	batch.ParallelChunks(len(in), batchInverseMinChunk, func(start, end int) {
+		batch.InverseChunk(in[start:end], res[start:end],
+			func(e *GoldilocksField) bool { return e.IsZero() },
+			func(a, b *GoldilocksField) GoldilocksField { return MulF(*a, *b) },
+			func(e *GoldilocksField) GoldilocksField { return InverseF(*e) },
+			OneF(),
+		)
+	})
+	return res
+}
@@ -0,0 +1,96 @@
+package goldilocks
+
+// Field is the arithmetic shared by the two representations of the Goldilocks
+// field, Element and GoldilocksField, in the pointer-receiver style of gnark:
+// z.Add(a, b) sets z = a + b and returns z. Generic code takes a pair of type
+// parameters [F any, PF Field[F]] and works on values of type F:
+//
+//	func double[F any, PF Field[F]](x F) F {
+//		PF(&x).Double(&x)
+//		return x
+//	}
+type Field[F any] interface {
+	*F
+	Set(a *F) *F
+	SetZero() *F
+	SetOne() *F
+	SetUint64(v uint64) *F
+	Add(a, b *F) *F
+	Sub(a, b *F) *F
+	Double(a *F) *F
+	Neg(a *F) *F
+	Mul(a, b *F) *F
+	Square(a *F) *F
+	IsZero() bool
+	Equal(a *F) bool
+	// Canonical value in [0, ORDER).
+	Uint64() uint64
+}
+
+func implementsField[F any, PF Field[F]]() {}
+
+var (
+	_ = implementsField[Element, *Element]
+	_ = implementsField[GoldilocksField, *GoldilocksField]
+)
+
+// Pointer-receiver API of GoldilocksField, see Field.
+
+func (z *GoldilocksField) Set(a *GoldilocksField) *GoldilocksField {
+	*z = *a
+	return z
+}
+
+func (z *GoldilocksField) SetZero() *GoldilocksField {
+	*z = 0
+	return z
+}
+
+func (z *GoldilocksField) SetOne() *GoldilocksField {
+	*z = 1
+	return z
+}
+
+// Reduces v modulo ORDER.
+func (z *GoldilocksField) SetUint64(v uint64) *GoldilocksField {
+	*z = GoldilocksField(GoldilocksField(v).ToCanonicalUint64())
+	return z
+}
+
+func (z *GoldilocksField) Add(a, b *GoldilocksField) *GoldilocksField {
+	*z = AddF(*a, *b)
+	return z
+}
+
+func (z *GoldilocksField) Sub(a, b *GoldilocksField) *GoldilocksField {
+	*z = SubF(*a, *b)
+	return z
+}
+
+func (z *GoldilocksField) Double(a *GoldilocksField) *GoldilocksField {
+	*z = DoubleF(*a)
+	return z
+}
+
+func (z *GoldilocksField) Neg(a *GoldilocksField) *GoldilocksField {
+	*z = NegF(*a)
+	return z
+}
+
+func (z *GoldilocksField) Mul(a, b *GoldilocksField) *GoldilocksField {
+	*z = MulF(*a, *b)
+	return z
+}
+
+func (z *GoldilocksField) Square(a *GoldilocksField) *GoldilocksField {
+	*z = SquareF(*a)
+	return z
+}
+
+func (z *GoldilocksField) Equal(a *GoldilocksField) bool {
+	return EqualsF(*z, *a)
+}
+
+func (z *GoldilocksField) Uint64() uint64 {
+	return z.ToCanonicalUint64()
+}
@@ -3,7 +3,9 @@ package goldilocks
 // Partially wraps and extends the functionality of the goldilocks field package.
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 
 	g "github.com/consensys/gnark-crypto/field/goldilocks"
 )
@@ -143,17 +145,40 @@ func NegOne() *Element {
 }
 
 func Sample() Element {
-	elem := g.NewElement(0)
-	elem.SetRandom()
+	elem, err := SampleFrom(rand.Reader)
+	if err != nil {
+		panic("failed to read random bytes into buffer")
+	}
 	return elem
 }
 
+// Samples a uniform field element from the bytes of r, see SampleFFrom.
+func SampleFrom(r io.Reader) (Element, error) {
+	v, err := sampleCanonicalUint64(r)
+	if err != nil {
+		return Element{}, err
+	}
+	return g.NewElement(v), nil
+}
+
 func RandArray(count int) []Element {
+	ret, err := RandArrayFrom(rand.Reader, count)
+	if err != nil {
+		panic("failed to read random bytes into buffer")
+	}
+	return ret
+}
+
+func RandArrayFrom(r io.Reader, count int) ([]Element, error) {
 	ret := make([]Element, count)
 	for i := 0; i < count; i++ {
-		ret[i] = Sample()
+		elem, err := SampleFrom(r)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = elem
 	}
-	return ret
+	return ret, nil
 }
 
 func Add(elems ...Element) Element {
@@ -3,6 +3,8 @@ package goldilocks
 import (
 	"crypto/rand"
 	"encoding/binary"
+	"fmt"
+	"io"
 	"math/big"
 	"math/bits"
 )
@@ -12,7 +14,16 @@ type GoldilocksField uint64
 const EPSILON = uint64((1 << 32) - 1)
 const ORDER = uint64(0xffffffff00000001)
 
-var ORDER_BIG, _ = new(big.Int).SetString("0xffffffff00000001", 16)
+var ORDER_BIG = new(big.Int).SetUint64(ORDER)
+
+const (
+	// p - 1 = 2^32 * (2^32 - 1)
+	TWO_ADICITY = 32
+	// Generator of the multiplicative group of the field.
+	MULTIPLICATIVE_GROUP_GENERATOR = GoldilocksField(7)
+	// MULTIPLICATIVE_GROUP_GENERATOR^((p - 1) / 2^TWO_ADICITY), a primitive 2^TWO_ADICITY-th root of unity.
+	POWER_OF_TWO_GENERATOR = GoldilocksField(1753635133440165772)
+)
 
 func NonCannonicalGoldilocksField(x int64) GoldilocksField {
 	if x < 0 {
@@ -114,11 +125,32 @@ func NegF(x GoldilocksField) GoldilocksField {
 }
 
 func SampleF() GoldilocksField {
-	rng, err := rand.Int(rand.Reader, ORDER_BIG)
+	x, err := SampleFFrom(rand.Reader)
 	if err != nil {
 		panic("failed to read random bytes into buffer")
 	}
-	return GoldilocksField(rng.Uint64())
+	return x
+}
+
+// Samples a uniform field element from the bytes of r. Pass crypto/rand.Reader
+// for secrets, or a seeded XOF such as SHAKE256 for reproducible output.
+func SampleFFrom(r io.Reader) (GoldilocksField, error) {
+	v, err := sampleCanonicalUint64(r)
+	return GoldilocksField(v), err
+}
+
+// Rejection sampling: draws 8 little-endian bytes until they encode a value
+// below ORDER, which takes more than one draw with probability 2^-32.
+func sampleCanonicalUint64(r io.Reader) (uint64, error) {
+	var buf [Bytes]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		if v := binary.LittleEndian.Uint64(buf[:]); v < ORDER {
+			return v, nil
+		}
+	}
 }
 
 func ToLittleEndianBytesF(z GoldilocksField) []byte {
@@ -127,28 +159,119 @@ func ToLittleEndianBytesF(z GoldilocksField) []byte {
 	return res
 }
 
+// Decodes 8 little-endian bytes into a field element, without checking that the
+// value is below ORDER.
+//
+// Deprecated: use FromCanonicalLittleEndianBytesStrictF, which rejects
+// non-canonical encodings.
 func FromCanonicalLittleEndianBytesF(b []byte) GoldilocksField {
 	return GoldilocksField(binary.LittleEndian.Uint64(b))
 }
 
-// func (z *GoldilocksField) Inverse(x *GoldilocksField) *GoldilocksField {
-// 	if x.IsZero() {
-// 		z.SetZero()
-// 		return z
-// 	}
+// Decodes 8 little-endian bytes into a field element. Values not below ORDER are
+// rejected, so every element has exactly one valid encoding.
+func FromCanonicalLittleEndianBytesStrictF(b []byte) (GoldilocksField, error) {
+	if len(b) != Bytes {
+		return 0, fmt.Errorf("input bytes len should be %d but is %d", Bytes, len(b))
+	}
+	x := binary.LittleEndian.Uint64(b)
+	if x >= ORDER {
+		return 0, fmt.Errorf("non-canonical field element encoding: %d is not below the field order", x)
+	}
+	return GoldilocksField(x), nil
+}
+
+// Decodes a concatenation of canonical 8-byte little-endian encodings.
+func ArrayFromCanonicalLittleEndianBytesF(b []byte) ([]GoldilocksField, error) {
+	if len(b)%Bytes != 0 {
+		return nil, fmt.Errorf("input bytes len should be multiple of %d but is %d", Bytes, len(b))
+	}
+	res := make([]GoldilocksField, len(b)/Bytes)
+	for i := range res {
+		elem, err := FromCanonicalLittleEndianBytesStrictF(b[i*Bytes : (i+1)*Bytes])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert bytes to field element at index %d: %w", i, err)
+		}
+		res[i] = elem
+	}
+	return res, nil
+}
+
+// Computes x^(p-2) with 72 multiplications using the addition chain from plonky2.
+// The exponent p - 2 is represented in binary as:
+// 0b1111111111111111111111111111111011111111111111111111111111111111
+// Returns zero if x is zero.
+func InverseF(x GoldilocksField) GoldilocksField {
+	t2 := MulF(SquareF(x), x)              // 0b11
+	t3 := MulF(SquareF(t2), x)             // 0b111
+	t6 := MulF(ExpPowerOf2(t3, 3), t3)     // 0b111111
+	t12 := MulF(ExpPowerOf2(t6, 6), t6)    // 12 ones
+	t24 := MulF(ExpPowerOf2(t12, 12), t12) // 24 ones
+	t30 := MulF(ExpPowerOf2(t24, 6), t6)   // 30 ones
+	t31 := MulF(SquareF(t30), x)           // 31 ones
+	t63 := MulF(ExpPowerOf2(t31, 32), t31) // 31 ones, 0, 31 ones
+
+	return MulF(SquareF(t63), x)
+}
+
+func DivF(lhs, rhs GoldilocksField) GoldilocksField {
+	return MulF(lhs, InverseF(rhs))
+}
+
+func ExpF(x GoldilocksField, power uint64) GoldilocksField {
+	res := OneF()
+	for i := bits.Len64(power) - 1; i >= 0; i-- {
+		res = SquareF(res)
+		if (power>>uint(i))&1 == 1 {
+			res = MulF(res, x)
+		}
+	}
+
+	return res
+}
+
+func EqualsF(lhs, rhs GoldilocksField) bool {
+	return lhs.ToCanonicalUint64() == rhs.ToCanonicalUint64()
+}
 
-// 	var tmp *GoldilocksField
+// Returns 1 if x is a non-zero square, p-1 if it is a non-square and 0 if x is zero.
+func LegendreF(x GoldilocksField) GoldilocksField {
+	return GoldilocksField(ExpF(x, (ORDER-1)/2).ToCanonicalUint64())
+}
 
-// 	t2 := *tmp.Square(x).Mul(tmp, x)
-// 	t3 := *tmp.Square(&t2).Mul(tmp, x)
-// 	t6 := *tmp.ExpPowerOf2(&t3, 3).Mul(tmp, &t3)
-// 	t12 := *tmp.ExpPowerOf2(&t6, 6).Mul(tmp, &t6)
-// 	t24 := *tmp.ExpPowerOf2(&t12, 12).Mul(tmp, &t12)
-// 	t30 := *tmp.ExpPowerOf2(&t24, 6).Mul(tmp, &t6)
-// 	t31 := *tmp.Square(&t30).Mul(tmp, x)
-// 	t63 := *tmp.ExpPowerOf2(&t31, 32).Mul(tmp, &t31)
+// Tonelli-Shanks square root. Returns false if x is not a square.
+func SqrtF(x GoldilocksField) (GoldilocksField, bool) {
+	if x.IsZero() {
+		return ZeroF(), true
+	}
+	if !EqualsF(LegendreF(x), OneF()) {
+		return ZeroF(), false
+	}
 
-// 	z.Square(&t63).Mul(z, x)
+	// p - 1 = 2^TWO_ADICITY * t with t odd.
+	const t = (ORDER - 1) >> TWO_ADICITY
+
+	m := TWO_ADICITY
+	c := POWER_OF_TWO_GENERATOR
+	w := ExpF(x, (t-1)/2)
+	r := MulF(w, x) // x^((t+1)/2)
+	b := MulF(r, w) // x^t
+
+	for !EqualsF(b, OneF()) {
+		// Find the least i such that b^(2^i) = 1.
+		i := 1
+		b2 := SquareF(b)
+		for !EqualsF(b2, OneF()) {
+			b2 = SquareF(b2)
+			i++
+		}
+
+		c = ExpPowerOf2(c, uint(m-i-1))
+		r = MulF(r, c)
+		c = SquareF(c)
+		b = MulF(b, c)
+		m = i
+	}
 
-// 	return z
-// }
+	return GoldilocksField(r.ToCanonicalUint64()), true
+}
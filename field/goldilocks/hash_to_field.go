@@ -0,0 +1,95 @@
+package goldilocks
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// Bytes of expanded message per field element in HashToField: 128 bits more
+// than the 64 of the modulus, so that the reduction is statistically uniform.
+const HashToFieldL = 16 + Bytes
+
+// expand_message_xmd of RFC 9380 over SHA-256. dst is the domain-separation tag,
+// it must be non-empty and at most 255 bytes long, and lenInBytes at most 8160.
+func ExpandMessageXMD(msg, dst []byte, lenInBytes int) ([]byte, error) {
+	if len(dst) == 0 || len(dst) > 255 {
+		return nil, errors.New("domain-separation tag must be between 1 and 255 bytes long")
+	}
+	ell := (lenInBytes + sha256.Size - 1) / sha256.Size
+	if lenInBytes < 0 || ell > 255 {
+		return nil, errors.New("expand_message_xmd can output at most 8160 bytes")
+	}
+
+	// DST_prime = DST || I2OSP(len(DST), 1)
+	dstPrime := append(append(make([]byte, 0, len(dst)+1), dst...), byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || I2OSP(len_in_bytes, 2) || I2OSP(0, 1) || DST_prime)
+	h := sha256.New()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	// b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime)
+	res := make([]byte, 0, ell*sha256.Size)
+	bi := make([]byte, sha256.Size)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+		res = append(res, bi...)
+	}
+	return res[:lenInBytes], nil
+}
+
+// Hashes msg to count uniformly distributed field elements, following
+// hash_to_field of RFC 9380 with expand_message_xmd over SHA-256 and
+// L = HashToFieldL. At most 8160 / HashToFieldL = 340 elements can be produced
+// by a single call.
+func HashToField(msg, dst []byte, count int) ([]Element, error) {
+	elems, err := HashToFieldF(msg, dst, count)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Element, count)
+	for i := range elems {
+		res[i].SetUint64(uint64(elems[i]))
+	}
+	return res, nil
+}
+
+// Like HashToField, returning GoldilocksField elements.
+func HashToFieldF(msg, dst []byte, count int) ([]GoldilocksField, error) {
+	if count < 0 {
+		return nil, errors.New("count must not be negative")
+	}
+	uniform, err := ExpandMessageXMD(msg, dst, count*HashToFieldL)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]GoldilocksField, count)
+	for i := range res {
+		res[i] = reduceBigEndian192(uniform[i*HashToFieldL : (i+1)*HashToFieldL])
+	}
+	return res, nil
+}
+
+// hi * 2^128 + mid * 2^64 + lo modulo ORDER, with 2^64 = EPSILON and
+// 2^128 = -2^32 modulo ORDER.
+func reduceBigEndian192(b []byte) GoldilocksField {
+	hi := GoldilocksField(binary.BigEndian.Uint64(b[0:8]))
+	mid := GoldilocksField(binary.BigEndian.Uint64(b[8:16]))
+	lo := GoldilocksField(binary.BigEndian.Uint64(b[16:24]))
+
+	res := AddF(lo, MulF(mid, GoldilocksField(EPSILON)))
+	res = SubF(res, MulF(hi, GoldilocksField(1<<32)))
+	return GoldilocksField(res.ToCanonicalUint64())
+}
@@ -0,0 +1,47 @@
+package goldilocks
+
+import (
+	"github.com/ppd0705/poseidon_crypto/internal/marshal"
+)
+
+// The binary form of a GoldilocksField is its canonical 8-byte little-endian
+// encoding, the text form is the hex of the binary one and the JSON form is the
+// text as a string. Decoding rejects non-canonical values.
+
+func (z GoldilocksField) MarshalBinary() ([]byte, error) {
+	return ToLittleEndianBytesF(z), nil
+}
+
+func (z *GoldilocksField) UnmarshalBinary(data []byte) error {
+	elem, err := FromCanonicalLittleEndianBytesStrictF(data)
+	if err != nil {
+		return err
+	}
+	*z = elem
+	return nil
+}
+
+func (z GoldilocksField) MarshalText() ([]byte, error) {
+	return marshal.EncodeHex(ToLittleEndianBytesF(z)), nil
+}
+
+func (z *GoldilocksField) UnmarshalText(text []byte) error {
+	data, err := marshal.DecodeHex(text, Bytes)
+	if err != nil {
+		return err
+	}
+	return z.UnmarshalBinary(data)
+}
+
+func (z GoldilocksField) MarshalJSON() ([]byte, error) {
+	text, _ := z.MarshalText()
+	return marshal.MarshalJSONText(text)
+}
+
+func (z *GoldilocksField) UnmarshalJSON(data []byte) error {
+	text, err := marshal.UnmarshalJSONText(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return z.UnmarshalText(text)
+}
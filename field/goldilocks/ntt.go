@@ -0,0 +1,167 @@
+package goldilocks
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+)
+
+// Powers of a primitive root of unity, only the first half of the domain is needed
+// by the butterflies.
+type twiddles struct {
+	forward []GoldilocksField
+	inverse []GoldilocksField
+}
+
+var (
+	twiddlesMu    sync.Mutex
+	twiddlesCache = map[int]*twiddles{}
+)
+
+// Returns a primitive 2^logN-th root of unity.
+func PrimitiveRootOfUnityF(logN int) GoldilocksField {
+	if logN < 0 || logN > TWO_ADICITY {
+		panic(fmt.Sprintf("no 2^%d-th root of unity in the Goldilocks field", logN))
+	}
+	return ExpPowerOf2(POWER_OF_TWO_GENERATOR, uint(TWO_ADICITY-logN))
+}
+
+// Returns the 2^logN points of the subgroup generated by PrimitiveRootOfUnityF(logN),
+// in the order NTT evaluates them.
+func TwoAdicSubgroupF(logN int) []GoldilocksField {
+	return PowersF(PrimitiveRootOfUnityF(logN), 1<<logN)
+}
+
+// Powers starting from 1
+func PowersF(e GoldilocksField, count int) []GoldilocksField {
+	ret := make([]GoldilocksField, count)
+	if count == 0 {
+		return ret
+	}
+	ret[0] = OneF()
+	for i := 1; i < count; i++ {
+		ret[i] = MulF(ret[i-1], e)
+	}
+	return ret
+}
+
+func getTwiddles(logN int) *twiddles {
+	twiddlesMu.Lock()
+	defer twiddlesMu.Unlock()
+
+	if t, ok := twiddlesCache[logN]; ok {
+		return t
+	}
+
+	root := PrimitiveRootOfUnityF(logN)
+	half := (1 << logN) >> 1
+	t := &twiddles{
+		forward: PowersF(root, half),
+		inverse: PowersF(InverseF(root), half),
+	}
+	twiddlesCache[logN] = t
+	return t
+}
+
+func log2Strict(n int) int {
+	if n <= 0 || n&(n-1) != 0 {
+		panic(fmt.Sprintf("length %d is not a power of two", n))
+	}
+	return bits.TrailingZeros(uint(n))
+}
+
+func bitReverse(a []GoldilocksField, logN int) {
+	if logN == 0 {
+		return
+	}
+	for i := range a {
+		j := int(bits.Reverse64(uint64(i)) >> (64 - logN))
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+}
+
+// In-place iterative radix-2 Cooley-Tukey transform.
+func nttInPlace(a []GoldilocksField, table []GoldilocksField, logN int) {
+	n := len(a)
+	bitReverse(a, logN)
+
+	for m := 1; m < n; m <<= 1 {
+		stride := n / (2 * m)
+		for start := 0; start < n; start += 2 * m {
+			for j := 0; j < m; j++ {
+				w := table[j*stride]
+				u := a[start+j]
+				v := MulF(a[start+j+m], w)
+				a[start+j] = AddF(u, v)
+				a[start+j+m] = SubF(u, v)
+			}
+		}
+	}
+}
+
+// Evaluates the polynomial with the given coefficients over the subgroup of
+// order len(coeffs), i.e. returns p(w^i) for i = 0..n-1. The length must be a
+// power of two no larger than 2^TWO_ADICITY.
+func NTT(coeffs []GoldilocksField) []GoldilocksField {
+	logN := log2Strict(len(coeffs))
+	res := make([]GoldilocksField, len(coeffs))
+	copy(res, coeffs)
+	nttInPlace(res, getTwiddles(logN).forward, logN)
+	return res
+}
+
+// Interpolates the polynomial taking the given values over the subgroup of order
+// len(evals). Inverse of NTT.
+func INTT(evals []GoldilocksField) []GoldilocksField {
+	logN := log2Strict(len(evals))
+	res := make([]GoldilocksField, len(evals))
+	copy(res, evals)
+	nttInPlace(res, getTwiddles(logN).inverse, logN)
+
+	nInv := InverseF(GoldilocksField(len(evals)))
+	for i := range res {
+		res[i] = MulF(res[i], nInv)
+	}
+	return res
+}
+
+// Evaluates the polynomial over the coset shift*H, where H is the subgroup of
+// order len(coeffs).
+func CosetNTT(coeffs []GoldilocksField, shift GoldilocksField) []GoldilocksField {
+	scaled := make([]GoldilocksField, len(coeffs))
+	s := OneF()
+	for i := range coeffs {
+		scaled[i] = MulF(coeffs[i], s)
+		s = MulF(s, shift)
+	}
+
+	logN := log2Strict(len(scaled))
+	nttInPlace(scaled, getTwiddles(logN).forward, logN)
+	return scaled
+}
+
+// Interpolates the polynomial taking the given values over the coset shift*H.
+// Inverse of CosetNTT.
+func CosetINTT(evals []GoldilocksField, shift GoldilocksField) []GoldilocksField {
+	res := INTT(evals)
+	shiftInv := InverseF(shift)
+	s := OneF()
+	for i := range res {
+		res[i] = MulF(res[i], s)
+		s = MulF(s, shiftInv)
+	}
+	return res
+}
+
+// Low-degree extension: takes the evaluations of a polynomial over the subgroup
+// of order n and returns its evaluations over the coset g*H' of the subgroup of
+// order n*2^rateBits, where g is MULTIPLICATIVE_GROUP_GENERATOR. This is the
+// Reed-Solomon encoding used by plonky2.
+func LowDegreeExtension(evals []GoldilocksField, rateBits int) []GoldilocksField {
+	coeffs := INTT(evals)
+	padded := make([]GoldilocksField, len(coeffs)<<rateBits)
+	copy(padded, coeffs)
+	return CosetNTT(padded, MULTIPLICATIVE_GROUP_GENERATOR)
+}
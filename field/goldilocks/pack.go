@@ -0,0 +1,75 @@
+package goldilocks
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Bytes stored in each element by PackBytesF. 7 bytes are always below ORDER,
+// so every chunk is a canonical element.
+const PackedBytesPerElement = 7
+
+// Packs an arbitrary byte string into field elements, injectively: the first
+// element is the length of b, followed by b in chunks of PackedBytesPerElement
+// little-endian bytes, the last one zero-padded.
+func PackBytesF(b []byte) []GoldilocksField {
+	n := (len(b) + PackedBytesPerElement - 1) / PackedBytesPerElement
+	res := make([]GoldilocksField, 1+n)
+	res[0] = GoldilocksField(len(b))
+
+	var chunk [8]byte
+	for i := 0; i < n; i++ {
+		chunk = [8]byte{}
+		copy(chunk[:PackedBytesPerElement], b[i*PackedBytesPerElement:])
+		res[1+i] = GoldilocksField(binary.LittleEndian.Uint64(chunk[:]))
+	}
+	return res
+}
+
+// Inverse of PackBytesF. Rejects any slice PackBytesF does not output.
+func UnpackBytesF(elems []GoldilocksField) ([]byte, error) {
+	if len(elems) == 0 {
+		return nil, fmt.Errorf("packed bytes should start with their length")
+	}
+	length := elems[0].ToCanonicalUint64()
+	n := (length + PackedBytesPerElement - 1) / PackedBytesPerElement
+	if uint64(len(elems)-1) != n {
+		return nil, fmt.Errorf("%d bytes should be packed into %d elements but got %d", length, n, len(elems)-1)
+	}
+
+	res := make([]byte, 0, n*PackedBytesPerElement)
+	var chunk [8]byte
+	for i := 1; i < len(elems); i++ {
+		v := elems[i].ToCanonicalUint64()
+		if v>>(8*PackedBytesPerElement) != 0 {
+			return nil, fmt.Errorf("packed element %d does not fit in %d bytes", i, PackedBytesPerElement)
+		}
+		binary.LittleEndian.PutUint64(chunk[:], v)
+		res = append(res, chunk[:PackedBytesPerElement]...)
+	}
+	for _, b := range res[length:] {
+		if b != 0 {
+			return nil, fmt.Errorf("padding of packed bytes should be zero")
+		}
+	}
+	return res[:length], nil
+}
+
+// Like PackBytesF, returning Elements.
+func PackBytes(b []byte) []Element {
+	packed := PackBytesF(b)
+	res := make([]Element, len(packed))
+	for i := range packed {
+		res[i].SetUint64(uint64(packed[i]))
+	}
+	return res
+}
+
+// Inverse of PackBytes.
+func UnpackBytes(elems []Element) ([]byte, error) {
+	packed := make([]GoldilocksField, len(elems))
+	for i := range elems {
+		packed[i] = GoldilocksField(elems[i].Uint64())
+	}
+	return UnpackBytesF(packed)
+}
@@ -0,0 +1,135 @@
+package goldilocks
+
+import "fmt"
+
+// Slice kernels over GoldilocksField. All slices passed to a kernel must have the
+// same length, and dst may alias any of the inputs. Results are not necessarily in
+// canonical form, as with the scalar operations.
+
+func checkVecLen(lens ...int) {
+	for _, l := range lens[1:] {
+		if l != lens[0] {
+			// Copied so that lens does not escape, and callers do not allocate.
+			panic(fmt.Sprintf("vector length mismatch: %v", append([]int(nil), lens...)))
+		}
+	}
+}
+
+// dst[i] = a[i] + b[i]
+func AddVec(dst, a, b []GoldilocksField) {
+	checkVecLen(len(dst), len(a), len(b))
+	addVec(dst, a, b)
+}
+
+// dst[i] = a[i] - b[i]
+func SubVec(dst, a, b []GoldilocksField) {
+	checkVecLen(len(dst), len(a), len(b))
+	subVec(dst, a, b)
+}
+
+// dst[i] = a[i] * b[i]
+func MulVec(dst, a, b []GoldilocksField) {
+	checkVecLen(len(dst), len(a), len(b))
+	mulVec(dst, a, b)
+}
+
+// dst[i] = c * a[i]
+func ScalarMulVec(dst, a []GoldilocksField, c GoldilocksField) {
+	checkVecLen(len(dst), len(a))
+	scalarMulVec(dst, a, c)
+}
+
+// dst[i] += alpha * x[i]
+func AxpyVec(dst []GoldilocksField, alpha GoldilocksField, x []GoldilocksField) {
+	checkVecLen(len(dst), len(x))
+	axpyVec(dst, alpha, x)
+}
+
+// dst[i] = a[i]^7, the Poseidon and Poseidon2 s-box.
+func Pow7Vec(dst, a []GoldilocksField) {
+	checkVecLen(len(dst), len(a))
+	pow7Vec(dst, a)
+}
+
+// Returns sum_i a[i] * b[i].
+func InnerProduct(a, b []GoldilocksField) GoldilocksField {
+	checkVecLen(len(a), len(b))
+	return innerProduct(a, b)
+}
+
+// dst[i] = sum_j coeffs[j] * vecs[j][i]
+func LinearCombination(dst []GoldilocksField, coeffs []GoldilocksField, vecs [][]GoldilocksField) {
+	checkVecLen(len(coeffs), len(vecs))
+	for _, v := range vecs {
+		checkVecLen(len(dst), len(v))
+	}
+
+	if len(vecs) == 0 {
+		for i := range dst {
+			dst[i] = ZeroF()
+		}
+		return
+	}
+
+	// The first vector may alias dst, so it is consumed before dst is written.
+	scalarMulVec(dst, vecs[0], coeffs[0])
+	for j := 1; j < len(vecs); j++ {
+		axpyVec(dst, coeffs[j], vecs[j])
+	}
+}
+
+func addVecGeneric(dst, a, b []GoldilocksField) {
+	for i := range dst {
+		dst[i] = AddF(a[i], b[i])
+	}
+}
+
+func subVecGeneric(dst, a, b []GoldilocksField) {
+	for i := range dst {
+		dst[i] = SubF(a[i], b[i])
+	}
+}
+
+func mulVecGeneric(dst, a, b []GoldilocksField) {
+	for i := range dst {
+		dst[i] = MulF(a[i], b[i])
+	}
+}
+
+func scalarMulVecGeneric(dst, a []GoldilocksField, c GoldilocksField) {
+	for i := range dst {
+		dst[i] = MulF(a[i], c)
+	}
+}
+
+func axpyVecGeneric(dst []GoldilocksField, alpha GoldilocksField, x []GoldilocksField) {
+	for i := range dst {
+		dst[i] = AddF(dst[i], MulF(alpha, x[i]))
+	}
+}
+
+func pow7VecGeneric(dst, a []GoldilocksField) {
+	for i := range dst {
+		x2 := MulF(a[i], a[i])
+		x3 := MulF(x2, a[i])
+		x4 := MulF(x2, x2)
+		dst[i] = MulF(x3, x4)
+	}
+}
+
+func innerProductGeneric(a, b []GoldilocksField) GoldilocksField {
+	res := ZeroF()
+	for len(a) > 0 {
+		n := len(a)
+		if n > accMaxTerms {
+			n = accMaxTerms
+		}
+		var acc AccF
+		for i := 0; i < n; i++ {
+			acc.MulAdd(a[i], b[i])
+		}
+		res = AddF(res, acc.Reduce())
+		a, b = a[n:], b[n:]
+	}
+	return res
+}
@@ -0,0 +1,142 @@
+//go:build !purego
+
+package goldilocks
+
+import "golang.org/x/sys/cpu"
+
+var (
+	supportAVX2   = cpu.X86.HasAVX2
+	supportAVX512 = cpu.X86.HasAVX512F
+)
+
+// The assembly kernels process n elements, n being a multiple of the vector
+// width (4 for AVX2, 8 for AVX-512); the tail is handled by the generic code.
+
+//go:noescape
+func addVecAVX2(dst, a, b *GoldilocksField, n int)
+
+//go:noescape
+func subVecAVX2(dst, a, b *GoldilocksField, n int)
+
+//go:noescape
+func mulVecAVX2(dst, a, b *GoldilocksField, n int)
+
+//go:noescape
+func scalarMulVecAVX2(dst, a *GoldilocksField, c GoldilocksField, n int)
+
+//go:noescape
+func axpyVecAVX2(dst *GoldilocksField, alpha GoldilocksField, x *GoldilocksField, n int)
+
+//go:noescape
+func innerProductAVX2(a, b *GoldilocksField, n int, res *[4]GoldilocksField)
+
+//go:noescape
+func addVecAVX512(dst, a, b *GoldilocksField, n int)
+
+//go:noescape
+func subVecAVX512(dst, a, b *GoldilocksField, n int)
+
+//go:noescape
+func mulVecAVX512(dst, a, b *GoldilocksField, n int)
+
+//go:noescape
+func scalarMulVecAVX512(dst, a *GoldilocksField, c GoldilocksField, n int)
+
+//go:noescape
+func axpyVecAVX512(dst *GoldilocksField, alpha GoldilocksField, x *GoldilocksField, n int)
+
+//go:noescape
+func innerProductAVX512(a, b *GoldilocksField, n int, res *[8]GoldilocksField)
+
+// Returns the number of leading elements to hand to the assembly kernels, and
+// whether to use the AVX-512 ones.
+func vecSplit(n int) (int, bool) {
+	if supportAVX512 && n >= 8 {
+		return n &^ 7, true
+	}
+	if supportAVX2 && n >= 4 {
+		return n &^ 3, false
+	}
+	return 0, false
+}
+
+func addVec(dst, a, b []GoldilocksField) {
+	m, avx512 := vecSplit(len(dst))
+	if m > 0 {
+		if avx512 {
+			addVecAVX512(&dst[0], &a[0], &b[0], m)
+		} else {
+			addVecAVX2(&dst[0], &a[0], &b[0], m)
+		}
+	}
+	addVecGeneric(dst[m:], a[m:], b[m:])
+}
+
+func subVec(dst, a, b []GoldilocksField) {
+	m, avx512 := vecSplit(len(dst))
+	if m > 0 {
+		if avx512 {
+			subVecAVX512(&dst[0], &a[0], &b[0], m)
+		} else {
+			subVecAVX2(&dst[0], &a[0], &b[0], m)
+		}
+	}
+	subVecGeneric(dst[m:], a[m:], b[m:])
+}
+
+func mulVec(dst, a, b []GoldilocksField) {
+	m, avx512 := vecSplit(len(dst))
+	if m > 0 {
+		if avx512 {
+			mulVecAVX512(&dst[0], &a[0], &b[0], m)
+		} else {
+			mulVecAVX2(&dst[0], &a[0], &b[0], m)
+		}
+	}
+	mulVecGeneric(dst[m:], a[m:], b[m:])
+}
+
+func scalarMulVec(dst, a []GoldilocksField, c GoldilocksField) {
+	m, avx512 := vecSplit(len(dst))
+	if m > 0 {
+		if avx512 {
+			scalarMulVecAVX512(&dst[0], &a[0], c, m)
+		} else {
+			scalarMulVecAVX2(&dst[0], &a[0], c, m)
+		}
+	}
+	scalarMulVecGeneric(dst[m:], a[m:], c)
+}
+
+func axpyVec(dst []GoldilocksField, alpha GoldilocksField, x []GoldilocksField) {
+	m, avx512 := vecSplit(len(dst))
+	if m > 0 {
+		if avx512 {
+			axpyVecAVX512(&dst[0], alpha, &x[0], m)
+		} else {
+			axpyVecAVX2(&dst[0], alpha, &x[0], m)
+		}
+	}
+	axpyVecGeneric(dst[m:], alpha, x[m:])
+}
+
+func innerProduct(a, b []GoldilocksField) GoldilocksField {
+	m, avx512 := vecSplit(len(a))
+	res := innerProductGeneric(a[m:], b[m:])
+	if m > 0 {
+		if avx512 {
+			var lanes [8]GoldilocksField
+			innerProductAVX512(&a[0], &b[0], m, &lanes)
+			for _, l := range lanes {
+				res = AddF(res, l)
+			}
+		} else {
+			var lanes [4]GoldilocksField
+			innerProductAVX2(&a[0], &b[0], m, &lanes)
+			for _, l := range lanes {
+				res = AddF(res, l)
+			}
+		}
+	}
+	return res
+}
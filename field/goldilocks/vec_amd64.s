@@ -0,0 +1,387 @@
+//go:build !purego
+
+#include "textflag.h"
+
+DATA epsilon<>+0(SB)/8, $0x00000000ffffffff
+GLOBL epsilon<>(SB), RODATA|NOPTR, $8
+
+DATA signBit<>+0(SB)/8, $0x8000000000000000
+GLOBL signBit<>(SB), RODATA|NOPTR, $8
+
+// The vector macros below mirror AddF, SubF and MulF lane by lane, so that they
+// return the exact same (possibly non-canonical) representatives.
+
+// AVX2. Y15 holds EPSILON and Y14 the sign bit in every lane.
+
+// c = (x < y) as an all-ones mask, unsigned comparison.
+#define LTU_AVX2(x, y, c, t) \
+	VPXOR    x, Y14, c \
+	VPXOR    y, Y14, t \
+	VPCMPGTQ c, t, c
+
+// r = a + b. r may alias b but not a.
+#define ADD_AVX2(a, b, r, t0, t1, t2) \
+	VPADDQ a, b, r \
+	LTU_AVX2(r, a, t0, t1) \
+	VPAND  t0, Y15, t0 \
+	VPADDQ t0, r, r \
+	LTU_AVX2(r, t0, t1, t2) \
+	VPAND  t1, Y15, t1 \
+	VPADDQ t1, r, r
+
+// r = a - b. r may alias neither a nor b.
+#define SUB_AVX2(a, b, r, t0, t1, t2) \
+	VPSUBQ b, a, r \
+	LTU_AVX2(a, b, t0, t1) \
+	VPAND  t0, Y15, t0 \
+	LTU_AVX2(r, t0, t1, t2) \
+	VPSUBQ t0, r, r \
+	VPAND  t1, Y15, t1 \
+	VPSUBQ t1, r, r
+
+// r = a * b. r may alias a or b.
+#define MUL_AVX2(a, b, r, t0, t1, t2, t3, t4) \
+	VPSRLQ   $32, a, t0 \
+	VPSRLQ   $32, b, t1 \
+	VPMULUDQ a, b, t2 \
+	VPMULUDQ a, t1, t3 \
+	VPMULUDQ t0, b, t4 \
+	VPMULUDQ t0, t1, t0 \
+	VPSRLQ   $32, t2, t1 \
+	VPADDQ   t1, t3, t3 \
+	VPAND    t3, Y15, t1 \
+	VPADDQ   t4, t1, t1 \
+	VPSRLQ   $32, t3, t3 \
+	VPADDQ   t3, t0, t0 \
+	VPSRLQ   $32, t1, t4 \
+	VPADDQ   t4, t0, t0 \
+	VPSLLQ   $32, t1, t1 \
+	VPAND    t2, Y15, t2 \
+	VPOR     t1, t2, t2 \
+	VPSRLQ   $32, t0, t1 \
+	VPAND    t0, Y15, t0 \
+	LTU_AVX2(t2, t1, t3, t4) \
+	VPSUBQ   t1, t2, t2 \
+	VPAND    t3, Y15, t3 \
+	VPSUBQ   t3, t2, t2 \
+	VPSLLQ   $32, t0, t1 \
+	VPSUBQ   t0, t1, t1 \
+	VPADDQ   t1, t2, r \
+	LTU_AVX2(r, t1, t3, t4) \
+	VPAND    t3, Y15, t3 \
+	VPADDQ   t3, r, r
+
+#define LOAD_CONSTS_AVX2 \
+	VPBROADCASTQ epsilon<>(SB), Y15 \
+	VPBROADCASTQ signBit<>(SB), Y14
+
+// func addVecAVX2(dst, a, b *GoldilocksField, n int)
+TEXT ·addVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DX
+	MOVQ n+24(FP), CX
+	LOAD_CONSTS_AVX2
+
+loop:
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	ADD_AVX2(Y0, Y1, Y2, Y3, Y4, Y5)
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DX
+	ADDQ    $32, DI
+	SUBQ    $4, CX
+	JNZ     loop
+
+	VZEROUPPER
+	RET
+
+// func subVecAVX2(dst, a, b *GoldilocksField, n int)
+TEXT ·subVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DX
+	MOVQ n+24(FP), CX
+	LOAD_CONSTS_AVX2
+
+loop:
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	SUB_AVX2(Y0, Y1, Y2, Y3, Y4, Y5)
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DX
+	ADDQ    $32, DI
+	SUBQ    $4, CX
+	JNZ     loop
+
+	VZEROUPPER
+	RET
+
+// func mulVecAVX2(dst, a, b *GoldilocksField, n int)
+TEXT ·mulVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DX
+	MOVQ n+24(FP), CX
+	LOAD_CONSTS_AVX2
+
+loop:
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MUL_AVX2(Y0, Y1, Y2, Y3, Y4, Y5, Y6, Y7)
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DX
+	ADDQ    $32, DI
+	SUBQ    $4, CX
+	JNZ     loop
+
+	VZEROUPPER
+	RET
+
+// func scalarMulVecAVX2(dst, a *GoldilocksField, c GoldilocksField, n int)
+TEXT ·scalarMulVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ         dst+0(FP), DI
+	MOVQ         a+8(FP), SI
+	VPBROADCASTQ c+16(FP), Y1
+	MOVQ         n+24(FP), CX
+	LOAD_CONSTS_AVX2
+
+loop:
+	VMOVDQU (SI), Y0
+	MUL_AVX2(Y0, Y1, Y2, Y3, Y4, Y5, Y6, Y7)
+	VMOVDQU Y2, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DI
+	SUBQ    $4, CX
+	JNZ     loop
+
+	VZEROUPPER
+	RET
+
+// func axpyVecAVX2(dst *GoldilocksField, alpha GoldilocksField, x *GoldilocksField, n int)
+TEXT ·axpyVecAVX2(SB), NOSPLIT, $0-32
+	MOVQ         dst+0(FP), DI
+	VPBROADCASTQ alpha+8(FP), Y1
+	MOVQ         x+16(FP), SI
+	MOVQ         n+24(FP), CX
+	LOAD_CONSTS_AVX2
+
+loop:
+	VMOVDQU (SI), Y0
+	MUL_AVX2(Y0, Y1, Y2, Y3, Y4, Y5, Y6, Y7)
+	VMOVDQU (DI), Y0
+	ADD_AVX2(Y0, Y2, Y8, Y3, Y4, Y5)
+	VMOVDQU Y8, (DI)
+	ADDQ    $32, SI
+	ADDQ    $32, DI
+	SUBQ    $4, CX
+	JNZ     loop
+
+	VZEROUPPER
+	RET
+
+// func innerProductAVX2(a, b *GoldilocksField, n int, res *[4]GoldilocksField)
+TEXT ·innerProductAVX2(SB), NOSPLIT, $0-32
+	MOVQ  a+0(FP), SI
+	MOVQ  b+8(FP), DX
+	MOVQ  n+16(FP), CX
+	MOVQ  res+24(FP), DI
+	LOAD_CONSTS_AVX2
+	VPXOR Y8, Y8, Y8
+
+loop:
+	VMOVDQU (SI), Y0
+	VMOVDQU (DX), Y1
+	MUL_AVX2(Y0, Y1, Y2, Y3, Y4, Y5, Y6, Y7)
+	ADD_AVX2(Y2, Y8, Y8, Y3, Y4, Y5)
+	ADDQ    $32, SI
+	ADDQ    $32, DX
+	SUBQ    $4, CX
+	JNZ     loop
+
+	VMOVDQU Y8, (DI)
+	VZEROUPPER
+	RET
+
+// AVX-512. Z31 holds EPSILON in every lane, carries and borrows live in the
+// opmask registers.
+
+// r = a + b. r may alias b but not a.
+#define ADD_AVX512(a, b, r) \
+	VPADDQ  a, b, r \
+	VPCMPUQ $1, a, r, K1 \
+	VPADDQ  Z31, r, K1, r \
+	VPCMPUQ $1, Z31, r, K1, K2 \
+	VPADDQ  Z31, r, K2, r
+
+// r = a - b. r may alias neither a nor b.
+#define SUB_AVX512(a, b, r) \
+	VPSUBQ  b, a, r \
+	VPCMPUQ $1, b, a, K1 \
+	VPCMPUQ $1, Z31, r, K1, K2 \
+	VPSUBQ  Z31, r, K1, r \
+	VPSUBQ  Z31, r, K2, r
+
+// r = a * b. r may alias a or b.
+#define MUL_AVX512(a, b, r, t0, t1, t2, t3, t4) \
+	VPSRLQ   $32, a, t0 \
+	VPSRLQ   $32, b, t1 \
+	VPMULUDQ a, b, t2 \
+	VPMULUDQ a, t1, t3 \
+	VPMULUDQ t0, b, t4 \
+	VPMULUDQ t0, t1, t0 \
+	VPSRLQ   $32, t2, t1 \
+	VPADDQ   t1, t3, t3 \
+	VPANDQ   t3, Z31, t1 \
+	VPADDQ   t4, t1, t1 \
+	VPSRLQ   $32, t3, t3 \
+	VPADDQ   t3, t0, t0 \
+	VPSRLQ   $32, t1, t4 \
+	VPADDQ   t4, t0, t0 \
+	VPSLLQ   $32, t1, t1 \
+	VPANDQ   t2, Z31, t2 \
+	VPORQ    t1, t2, t2 \
+	VPSRLQ   $32, t0, t1 \
+	VPANDQ   t0, Z31, t0 \
+	VPCMPUQ  $1, t1, t2, K1 \
+	VPSUBQ   t1, t2, t2 \
+	VPSUBQ   Z31, t2, K1, t2 \
+	VPSLLQ   $32, t0, t1 \
+	VPSUBQ   t0, t1, t1 \
+	VPADDQ   t1, t2, r \
+	VPCMPUQ  $1, t1, r, K1 \
+	VPADDQ   Z31, r, K1, r
+
+// func addVecAVX512(dst, a, b *GoldilocksField, n int)
+TEXT ·addVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         dst+0(FP), DI
+	MOVQ         a+8(FP), SI
+	MOVQ         b+16(FP), DX
+	MOVQ         n+24(FP), CX
+	VPBROADCASTQ epsilon<>(SB), Z31
+
+loop:
+	VMOVDQU64 (SI), Z0
+	VMOVDQU64 (DX), Z1
+	ADD_AVX512(Z0, Z1, Z2)
+	VMOVDQU64 Z2, (DI)
+	ADDQ      $64, SI
+	ADDQ      $64, DX
+	ADDQ      $64, DI
+	SUBQ      $8, CX
+	JNZ       loop
+
+	VZEROUPPER
+	RET
+
+// func subVecAVX512(dst, a, b *GoldilocksField, n int)
+TEXT ·subVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         dst+0(FP), DI
+	MOVQ         a+8(FP), SI
+	MOVQ         b+16(FP), DX
+	MOVQ         n+24(FP), CX
+	VPBROADCASTQ epsilon<>(SB), Z31
+
+loop:
+	VMOVDQU64 (SI), Z0
+	VMOVDQU64 (DX), Z1
+	SUB_AVX512(Z0, Z1, Z2)
+	VMOVDQU64 Z2, (DI)
+	ADDQ      $64, SI
+	ADDQ      $64, DX
+	ADDQ      $64, DI
+	SUBQ      $8, CX
+	JNZ       loop
+
+	VZEROUPPER
+	RET
+
+// func mulVecAVX512(dst, a, b *GoldilocksField, n int)
+TEXT ·mulVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         dst+0(FP), DI
+	MOVQ         a+8(FP), SI
+	MOVQ         b+16(FP), DX
+	MOVQ         n+24(FP), CX
+	VPBROADCASTQ epsilon<>(SB), Z31
+
+loop:
+	VMOVDQU64 (SI), Z0
+	VMOVDQU64 (DX), Z1
+	MUL_AVX512(Z0, Z1, Z2, Z3, Z4, Z5, Z6, Z7)
+	VMOVDQU64 Z2, (DI)
+	ADDQ      $64, SI
+	ADDQ      $64, DX
+	ADDQ      $64, DI
+	SUBQ      $8, CX
+	JNZ       loop
+
+	VZEROUPPER
+	RET
+
+// func scalarMulVecAVX512(dst, a *GoldilocksField, c GoldilocksField, n int)
+TEXT ·scalarMulVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         dst+0(FP), DI
+	MOVQ         a+8(FP), SI
+	VPBROADCASTQ c+16(FP), Z1
+	MOVQ         n+24(FP), CX
+	VPBROADCASTQ epsilon<>(SB), Z31
+
+loop:
+	VMOVDQU64 (SI), Z0
+	MUL_AVX512(Z0, Z1, Z2, Z3, Z4, Z5, Z6, Z7)
+	VMOVDQU64 Z2, (DI)
+	ADDQ      $64, SI
+	ADDQ      $64, DI
+	SUBQ      $8, CX
+	JNZ       loop
+
+	VZEROUPPER
+	RET
+
+// func axpyVecAVX512(dst *GoldilocksField, alpha GoldilocksField, x *GoldilocksField, n int)
+TEXT ·axpyVecAVX512(SB), NOSPLIT, $0-32
+	MOVQ         dst+0(FP), DI
+	VPBROADCASTQ alpha+8(FP), Z1
+	MOVQ         x+16(FP), SI
+	MOVQ         n+24(FP), CX
+	VPBROADCASTQ epsilon<>(SB), Z31
+
+loop:
+	VMOVDQU64 (SI), Z0
+	MUL_AVX512(Z0, Z1, Z2, Z3, Z4, Z5, Z6, Z7)
+	VMOVDQU64 (DI), Z0
+	ADD_AVX512(Z0, Z2, Z8)
+	VMOVDQU64 Z8, (DI)
+	ADDQ      $64, SI
+	ADDQ      $64, DI
+	SUBQ      $8, CX
+	JNZ       loop
+
+	VZEROUPPER
+	RET
+
+// func innerProductAVX512(a, b *GoldilocksField, n int, res *[8]GoldilocksField)
+TEXT ·innerProductAVX512(SB), NOSPLIT, $0-32
+	MOVQ         a+0(FP), SI
+	MOVQ         b+8(FP), DX
+	MOVQ         n+16(FP), CX
+	MOVQ         res+24(FP), DI
+	VPBROADCASTQ epsilon<>(SB), Z31
+	VPXORQ       Z8, Z8, Z8
+
+loop:
+	VMOVDQU64 (SI), Z0
+	VMOVDQU64 (DX), Z1
+	MUL_AVX512(Z0, Z1, Z2, Z3, Z4, Z5, Z6, Z7)
+	ADD_AVX512(Z2, Z8, Z8)
+	ADDQ      $64, SI
+	ADDQ      $64, DX
+	SUBQ      $8, CX
+	JNZ       loop
+
+	VMOVDQU64 Z8, (DI)
+	VZEROUPPER
+	RET
@@ -0,0 +1,104 @@
+//go:build !purego
+
+package goldilocks
+
+import (
+	"testing"
+)
+
+// Runs f once with each of the kernels available on this CPU, and once with the
+// pure Go fallback.
+func forEachVecImpl(t *testing.T, f func(t *testing.T)) {
+	avx2, avx512 := supportAVX2, supportAVX512
+	defer func() { supportAVX2, supportAVX512 = avx2, avx512 }()
+
+	impls := []struct {
+		name          string
+		avx2, avx512  bool
+		supportedHere bool
+	}{
+		{"generic", false, false, true},
+		{"avx2", true, false, avx2},
+		{"avx512", false, true, avx512},
+	}
+	for _, impl := range impls {
+		if !impl.supportedHere {
+			continue
+		}
+		supportAVX2, supportAVX512 = impl.avx2, impl.avx512
+		t.Run(impl.name, f)
+	}
+}
+
+func TestVecKernelsMatchGeneric(t *testing.T) {
+	forEachVecImpl(t, func(t *testing.T) {
+		for n := 0; n < 40; n++ {
+			a, b := randVecF(n), randVecF(n)
+			c := randVecF(1)[0]
+
+			got, want := make([]GoldilocksField, n), make([]GoldilocksField, n)
+
+			addVec(got, a, b)
+			addVecGeneric(want, a, b)
+			checkVecEqual(t, "addVec", got, want)
+
+			subVec(got, a, b)
+			subVecGeneric(want, a, b)
+			checkVecEqual(t, "subVec", got, want)
+
+			mulVec(got, a, b)
+			mulVecGeneric(want, a, b)
+			checkVecEqual(t, "mulVec", got, want)
+
+			scalarMulVec(got, a, c)
+			scalarMulVecGeneric(want, a, c)
+			checkVecEqual(t, "scalarMulVec", got, want)
+
+			copy(got, b)
+			copy(want, b)
+			axpyVec(got, c, a)
+			axpyVecGeneric(want, c, a)
+			checkVecEqual(t, "axpyVec", got, want)
+
+			// The lanes are summed in a different order, only the canonical
+			// values agree.
+			ip, ipWant := innerProduct(a, b), innerProductGeneric(a, b)
+			if ip.ToCanonicalUint64() != ipWant.ToCanonicalUint64() {
+				t.Fatalf("innerProduct n=%d: expected %d, got %d", n, ipWant.ToCanonicalUint64(), ip.ToCanonicalUint64())
+			}
+		}
+	})
+}
+
+func TestVecKernelsAliasing(t *testing.T) {
+	forEachVecImpl(t, func(t *testing.T) {
+		n := 37
+		a, b := randVecF(n), randVecF(n)
+
+		want := make([]GoldilocksField, n)
+		mulVecGeneric(want, a, b)
+
+		got := make([]GoldilocksField, n)
+		copy(got, a)
+		mulVec(got, got, b)
+		checkVecEqual(t, "mulVec dst=a", got, want)
+
+		copy(got, b)
+		mulVec(got, a, got)
+		checkVecEqual(t, "mulVec dst=b", got, want)
+
+		subVecGeneric(want, a, b)
+		copy(got, a)
+		subVec(got, got, b)
+		checkVecEqual(t, "subVec dst=a", got, want)
+	})
+}
+
+func checkVecEqual(t *testing.T, name string, got, want []GoldilocksField) {
+	t.Helper()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s n=%d: mismatch at index %d: expected %d, got %d", name, len(want), i, want[i], got[i])
+		}
+	}
+}
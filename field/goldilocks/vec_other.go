@@ -0,0 +1,27 @@
+//go:build !amd64 || purego
+
+package goldilocks
+
+func addVec(dst, a, b []GoldilocksField) {
+	addVecGeneric(dst, a, b)
+}
+
+func subVec(dst, a, b []GoldilocksField) {
+	subVecGeneric(dst, a, b)
+}
+
+func mulVec(dst, a, b []GoldilocksField) {
+	mulVecGeneric(dst, a, b)
+}
+
+func scalarMulVec(dst, a []GoldilocksField, c GoldilocksField) {
+	scalarMulVecGeneric(dst, a, c)
+}
+
+func axpyVec(dst []GoldilocksField, alpha GoldilocksField, x []GoldilocksField) {
+	axpyVecGeneric(dst, alpha, x)
+}
+
+func innerProduct(a, b []GoldilocksField) GoldilocksField {
+	return innerProductGeneric(a, b)
+}
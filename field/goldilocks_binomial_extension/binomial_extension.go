@@ -0,0 +1,276 @@
+// Package goldilocks_binomial_extension implements the extensions
+// F[X]/(X^D - W) of the Goldilocks field used by plonky2, generically over the
+// degree D. Elements are arrays of D coefficients, from the constant term
+// upwards.
+package goldilocks_binomial_extension
+
+import (
+	"math/big"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// Parameters of the extension F[X]/(X^D - W). W must not be a d-th power for any
+// d dividing D, so that X^D - W is irreducible.
+type Params struct {
+	W g.GoldilocksField
+	// W^((p-1)/D), so that Frobenius(X) = DthRoot * X.
+	DthRoot g.GoldilocksField
+	// 2-adicity of the multiplicative group of the extension.
+	TwoAdicity int
+	// Coefficients of a generator of the multiplicative group of the extension.
+	MultiplicativeGroupGenerator []g.GoldilocksField
+	// Coefficients of a primitive 2^TwoAdicity-th root of unity.
+	PowerOfTwoGenerator []g.GoldilocksField
+}
+
+// Extension is satisfied by the array types of the supported degrees that
+// describe their field through Params.
+type Extension interface {
+	~[2]g.GoldilocksField | ~[3]g.GoldilocksField | ~[4]g.GoldilocksField | ~[5]g.GoldilocksField
+	Params() *Params
+}
+
+func Degree[E Extension]() int {
+	var e E
+	return len(e)
+}
+
+func params[E Extension]() *Params {
+	var e E
+	return e.Params()
+}
+
+func Zero[E Extension]() E {
+	var e E
+	return e
+}
+
+func One[E Extension]() E {
+	return FromBase[E](g.OneF())
+}
+
+func FromBase[E Extension](x g.GoldilocksField) E {
+	var e E
+	e[0] = x
+	return e
+}
+
+func fromCoeffs[E Extension](coeffs []g.GoldilocksField) E {
+	var e E
+	for i := 0; i < len(e); i++ {
+		e[i] = coeffs[i]
+	}
+	return e
+}
+
+func MultiplicativeGroupGenerator[E Extension]() E {
+	return fromCoeffs[E](params[E]().MultiplicativeGroupGenerator)
+}
+
+func PowerOfTwoGenerator[E Extension]() E {
+	return fromCoeffs[E](params[E]().PowerOfTwoGenerator)
+}
+
+func IsZero[E Extension](e E) bool {
+	for i := 0; i < len(e); i++ {
+		if !e[i].IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// Equality of the represented elements, the coefficients need not be canonical.
+func Equals[E Extension](a, b E) bool {
+	for i := 0; i < len(a); i++ {
+		if !g.EqualsF(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if the element lies in the base field, i.e. all the coefficients
+// of X^i with i > 0 are zero.
+func IsInBaseField[E Extension](e E) bool {
+	for i := 1; i < len(e); i++ {
+		if !e[i].IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func Add[E Extension](a, b E) E {
+	var res E
+	for i := 0; i < len(res); i++ {
+		res[i] = g.AddF(a[i], b[i])
+	}
+	return res
+}
+
+func Sub[E Extension](a, b E) E {
+	var res E
+	for i := 0; i < len(res); i++ {
+		res[i] = g.SubF(a[i], b[i])
+	}
+	return res
+}
+
+func Neg[E Extension](a E) E {
+	var res E
+	for i := 0; i < len(res); i++ {
+		res[i] = g.NegF(a[i])
+	}
+	return res
+}
+
+func Double[E Extension](a E) E {
+	return Add(a, a)
+}
+
+func ScalarMul[E Extension](a E, s g.GoldilocksField) E {
+	var res E
+	for i := 0; i < len(res); i++ {
+		res[i] = g.MulF(a[i], s)
+	}
+	return res
+}
+
+// Schoolbook product, X^(D+k) being reduced to W*X^k.
+func Mul[E Extension](a, b E) E {
+	w := a.Params().W
+
+	var res E
+	d := len(res)
+	for k := 0; k < d; k++ {
+		// Terms landing on X^k directly, and on X^(k+D) before reduction.
+		low, high := g.ZeroF(), g.ZeroF()
+		for i := 0; i <= k; i++ {
+			low = g.AddF(low, g.MulF(a[i], b[k-i]))
+		}
+		for i := k + 1; i < d; i++ {
+			high = g.AddF(high, g.MulF(a[i], b[k+d-i]))
+		}
+		res[k] = g.AddF(low, g.MulF(w, high))
+	}
+	return res
+}
+
+func Square[E Extension](a E) E {
+	return Mul(a, a)
+}
+
+func ExpPowerOf2[E Extension](x E, power int) E {
+	res := x
+	for i := 0; i < power; i++ {
+		res = Square(res)
+	}
+	return res
+}
+
+func Exp[E Extension](x E, power uint64) E {
+	return ExpBig(x, new(big.Int).SetUint64(power))
+}
+
+// Exponentiation by a non-negative big integer.
+func ExpBig[E Extension](x E, power *big.Int) E {
+	res := One[E]()
+	for i := power.BitLen() - 1; i >= 0; i-- {
+		res = Square(res)
+		if power.Bit(i) == 1 {
+			res = Mul(res, x)
+		}
+	}
+	return res
+}
+
+func Frobenius[E Extension](x E) E {
+	return RepeatedFrobenius(x, 1)
+}
+
+// Computes x^(p^count). Since X^p = DthRoot * X, the coefficient of X^i is
+// multiplied by DthRoot^(i*count).
+func RepeatedFrobenius[E Extension](x E, count int) E {
+	d := len(x)
+	count %= d
+	if count == 0 {
+		return x
+	}
+
+	z0 := g.ExpF(x.Params().DthRoot, uint64(count))
+	var res E
+	z := g.OneF()
+	for i := 0; i < len(res); i++ {
+		res[i] = g.MulF(x[i], z)
+		z = g.MulF(z, z0)
+	}
+	return res
+}
+
+// Returns the product of the conjugates of x other than x itself, so that
+// x * conjugatesProduct(x) is the norm of x.
+func conjugatesProduct[E Extension](x E) E {
+	res := Frobenius(x)
+	for k := 2; k < len(x); k++ {
+		res = Mul(res, RepeatedFrobenius(x, k))
+	}
+	return res
+}
+
+// Norm of x down to the base field, i.e. the product of its conjugates.
+func Norm[E Extension](x E) g.GoldilocksField {
+	return Mul(x, conjugatesProduct(x))[0]
+}
+
+// Returns the inverse of x, or zero if x is zero.
+func InverseOrZero[E Extension](x E) E {
+	f := conjugatesProduct(x)
+	norm := Mul(x, f)[0]
+	return ScalarMul(f, g.InverseF(norm))
+}
+
+// x is a square in the extension if and only if its norm is a square in the
+// base field.
+func IsSquare[E Extension](x E) bool {
+	return g.LegendreF(Norm(x)) != g.NegOneF()
+}
+
+// Tonelli-Shanks square root. Returns false if x is not a square.
+func Sqrt[E Extension](x E) (E, bool) {
+	if IsZero(x) {
+		return x, true
+	}
+	if !IsSquare(x) {
+		return Zero[E](), false
+	}
+
+	// p^D - 1 = 2^s * t with t odd.
+	p := x.Params()
+	order := new(big.Int).Exp(new(big.Int).SetUint64(g.ORDER), big.NewInt(int64(len(x))), nil)
+	t := new(big.Int).Rsh(order.Sub(order, big.NewInt(1)), uint(p.TwoAdicity))
+
+	m := p.TwoAdicity
+	c := PowerOfTwoGenerator[E]()
+	w := ExpBig(x, new(big.Int).Rsh(t, 1)) // x^((t-1)/2)
+	r := Mul(w, x)                         // x^((t+1)/2)
+	b := Mul(w, r)                         // x^t
+
+	one := One[E]()
+	for !Equals(b, one) {
+		// Least i such that b^(2^i) = 1.
+		i := 0
+		for bb := b; !Equals(bb, one); bb = Square(bb) {
+			i++
+		}
+
+		e := ExpPowerOf2(c, m-i-1)
+		m = i
+		c = Square(e)
+		b = Mul(b, c)
+		r = Mul(r, e)
+	}
+
+	return r, true
+}
@@ -0,0 +1,150 @@
+package goldilocks_binomial_extension
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+)
+
+// Same field as goldilocks_quintic_extension, to cross-check the generic code.
+type quintic [5]g.GoldilocksField
+
+var quinticParams = Params{
+	W:                   3,
+	DthRoot:             1041288259238279555,
+	TwoAdicity:          32,
+	PowerOfTwoGenerator: []g.GoldilocksField{g.POWER_OF_TWO_GENERATOR, 0, 0, 0, 0},
+}
+
+func (quintic) Params() *Params {
+	return &quinticParams
+}
+
+func sample[E Extension]() E {
+	var e E
+	for i := 0; i < len(e); i++ {
+		e[i] = g.GoldilocksField(rand.Uint64N(g.ORDER))
+	}
+	return e
+}
+
+func testFieldOps[E Extension](t *testing.T) {
+	d := Degree[E]()
+	p := params[E]()
+
+	if !g.EqualsF(p.DthRoot, g.ExpF(p.W, (g.ORDER-1)/uint64(d))) {
+		t.Fatalf("D=%d: DthRoot is not W^((p-1)/D)", d)
+	}
+
+	// X^D = W
+	var x E
+	x[1] = g.OneF()
+	if !Equals(Exp(x, uint64(d)), FromBase[E](p.W)) {
+		t.Fatalf("D=%d: expected X^D = W", d)
+	}
+
+	one := One[E]()
+	for i := 0; i < 20; i++ {
+		a, b, c := sample[E](), sample[E](), sample[E]()
+
+		if !Equals(Mul(Mul(a, b), c), Mul(a, Mul(b, c))) {
+			t.Fatalf("D=%d: multiplication is not associative", d)
+		}
+		if !Equals(Mul(a, Add(b, c)), Add(Mul(a, b), Mul(a, c))) {
+			t.Fatalf("D=%d: multiplication does not distribute over addition", d)
+		}
+		if !Equals(Sub(Add(a, b), b), a) || !IsZero(Add(a, Neg(a))) {
+			t.Fatalf("D=%d: addition and subtraction are not inverse", d)
+		}
+		if !Equals(Mul(a, InverseOrZero(a)), one) {
+			t.Fatalf("D=%d: a * a^-1 != 1", d)
+		}
+
+		pBig := new(big.Int).SetUint64(g.ORDER)
+		if !Equals(Frobenius(a), ExpBig(a, pBig)) {
+			t.Fatalf("D=%d: Frobenius(a) != a^p", d)
+		}
+		if !Equals(RepeatedFrobenius(a, d), a) {
+			t.Fatalf("D=%d: Frobenius^D is not the identity", d)
+		}
+		if !Equals(Mul(Frobenius(a), Frobenius(b)), Frobenius(Mul(a, b))) {
+			t.Fatalf("D=%d: Frobenius is not multiplicative", d)
+		}
+		if !IsInBaseField(Mul(a, conjugatesProduct(a))) {
+			t.Fatalf("D=%d: norm is not in the base field", d)
+		}
+
+		sq := Square(a)
+		root, ok := Sqrt(sq)
+		if !ok || !Equals(Square(root), sq) {
+			t.Fatalf("D=%d: failed to compute square root of a square", d)
+		}
+	}
+
+	if !IsZero(InverseOrZero(Zero[E]())) {
+		t.Fatalf("D=%d: expected inverse of zero to be zero", d)
+	}
+
+	// The generator of the 2-Sylow subgroup is a non-square.
+	gen := PowerOfTwoGenerator[E]()
+	if _, ok := Sqrt(gen); ok || IsSquare(gen) {
+		t.Fatalf("D=%d: expected the power of two generator not to be a square", d)
+	}
+	if !Equals(ExpPowerOf2(gen, p.TwoAdicity), one) || Equals(ExpPowerOf2(gen, p.TwoAdicity-1), one) {
+		t.Fatalf("D=%d: power of two generator does not have order 2^%d", d, p.TwoAdicity)
+	}
+
+	// The 2-adicity is the one of p^D - 1.
+	order := new(big.Int).Exp(new(big.Int).SetUint64(g.ORDER), big.NewInt(int64(d)), nil)
+	order.Sub(order, big.NewInt(1))
+	if int(order.TrailingZeroBits()) != p.TwoAdicity {
+		t.Fatalf("D=%d: expected 2-adicity %d, got %d", d, order.TrailingZeroBits(), p.TwoAdicity)
+	}
+}
+
+func TestQuadratic(t *testing.T) {
+	testFieldOps[Quadratic](t)
+
+	// A generator of the multiplicative group is in particular a non-square.
+	gen := MultiplicativeGroupGenerator[Quadratic]()
+	if IsSquare(gen) {
+		t.Fatalf("Expected the multiplicative group generator to be a non-square")
+	}
+}
+
+func TestQuartic(t *testing.T) {
+	testFieldOps[Quartic](t)
+
+	gen := MultiplicativeGroupGenerator[Quartic]()
+	if IsSquare(gen) {
+		t.Fatalf("Expected the multiplicative group generator to be a non-square")
+	}
+}
+
+func TestQuinticMatchesgFp5(t *testing.T) {
+	testFieldOps[quintic](t)
+
+	toFp5 := func(e quintic) gFp5.Element {
+		return gFp5.FromPlonky2GoldilocksField(e[:])
+	}
+
+	for i := 0; i < 20; i++ {
+		a, b := sample[quintic](), sample[quintic]()
+		if !gFp5.Equals(toFp5(Mul(a, b)), gFp5.Mul(toFp5(a), toFp5(b))) {
+			t.Fatalf("Mul does not match gFp5")
+		}
+		if !gFp5.Equals(toFp5(InverseOrZero(a)), gFp5.InverseOrZero(toFp5(a))) {
+			t.Fatalf("InverseOrZero does not match gFp5")
+		}
+		if !gFp5.Equals(toFp5(RepeatedFrobenius(a, 3)), gFp5.RepeatedFrobenius(toFp5(a), 3)) {
+			t.Fatalf("RepeatedFrobenius does not match gFp5")
+		}
+		_, ok := gFp5.Sqrt(toFp5(a))
+		if ok != IsSquare(a) {
+			t.Fatalf("IsSquare does not match gFp5")
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package goldilocks_binomial_extension
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// plonky2's QuadraticExtension<GoldilocksField>: F[X]/(X^2 - 7), used for FRI
+// challenges and openings.
+type Quadratic [2]g.GoldilocksField
+
+// plonky2's QuarticExtension<GoldilocksField>: F[X]/(X^4 - 7).
+type Quartic [4]g.GoldilocksField
+
+var (
+	QUADRATIC_PARAMS = Params{
+		W:                            7,
+		DthRoot:                      18446744069414584320,
+		TwoAdicity:                   33,
+		MultiplicativeGroupGenerator: []g.GoldilocksField{18081566051660590251, 16121475356294670766},
+		PowerOfTwoGenerator:          []g.GoldilocksField{0, 15659105665374529263},
+	}
+
+	QUARTIC_PARAMS = Params{
+		W:                            7,
+		DthRoot:                      281474976710656,
+		TwoAdicity:                   34,
+		MultiplicativeGroupGenerator: []g.GoldilocksField{5024755240244648895, 13227474371289740625, 3912887029498544536, 3900057112666848848},
+		PowerOfTwoGenerator:          []g.GoldilocksField{0, 0, 0, 12587610116473453104},
+	}
+)
+
+func (Quadratic) Params() *Params {
+	return &QUADRATIC_PARAMS
+}
+
+func (Quartic) Params() *Params {
+	return &QUARTIC_PARAMS
+}
@@ -0,0 +1,288 @@
+package goldilocks_quintic_extension
+
+import (
+	"math/bits"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// Constant-time variants of the operations that handle secret values. They
+// never branch on or index by the operands, and report failures through a flag
+// instead of returning early or panicking. Flags are masks: 0xFFFFFFFFFFFFFFFF
+// for true and 0 for false, so they can feed Select directly.
+//
+// gnark's base field arithmetic ends its reductions with data-dependent branches,
+// so these functions work on the Montgomery limbs directly.
+
+const (
+	ctQ       = g.ORDER
+	ctQInvNeg = uint64(18446744069414584319) // -q^-1 mod 2^64
+)
+
+// 1 in Montgomery form.
+var ctOne = g.One()
+
+// Returns 0xFFFFFFFFFFFFFFFF if x == 0 and 0 otherwise.
+func ctIsZero64(x uint64) uint64 {
+	// The top bit of x | -x is set if and only if x != 0.
+	return ((x | -x) >> 63) - 1
+}
+
+func ctSelectBase(c uint64, a0, a1 g.Element) g.Element {
+	return g.Element{a0[0] ^ (c & (a0[0] ^ a1[0]))}
+}
+
+func ctAddBase(a, b g.Element) g.Element {
+	s, carry := bits.Add64(a[0], b[0], 0)
+	r, borrow := bits.Sub64(s, ctQ, 0)
+	// Keep s only if a + b < q, i.e. no carry and the subtraction borrowed.
+	keep := -(borrow &^ carry)
+	return g.Element{(s & keep) | (r &^ keep)}
+}
+
+func ctSubBase(a, b g.Element) g.Element {
+	d, borrow := bits.Sub64(a[0], b[0], 0)
+	return g.Element{d + (ctQ & -borrow)}
+}
+
+func ctNegBase(a g.Element) g.Element {
+	return ctSubBase(g.Element{}, a)
+}
+
+// Montgomery product with a masked final subtraction.
+func ctMulBase(a, b g.Element) g.Element {
+	hi, lo := bits.Mul64(a[0], b[0])
+	m := lo * ctQInvNeg
+	mHi, mLo := bits.Mul64(m, ctQ)
+	_, carry := bits.Add64(lo, mLo, 0)
+	t, carry := bits.Add64(hi, mHi, carry)
+
+	// t + carry*2^64 < 2q, subtract q once if it is not below q.
+	r, borrow := bits.Sub64(t, ctQ, 0)
+	keep := -(borrow &^ carry)
+	return g.Element{(t & keep) | (r &^ keep)}
+}
+
+func ctSquareBase(a g.Element) g.Element {
+	return ctMulBase(a, a)
+}
+
+// Square-and-multiply over all the bits of the public exponent e.
+func ctExpBase(x g.Element, e uint64) g.Element {
+	res := ctOne
+	for i := 63; i >= 0; i-- {
+		res = ctSquareBase(res)
+		if (e>>uint(i))&1 == 1 {
+			res = ctMulBase(res, x)
+		}
+	}
+	return res
+}
+
+func ctEqualBase(a, b g.Element) uint64 {
+	return ctIsZero64(a[0] ^ b[0])
+}
+
+// x^(p-2), zero if x is zero.
+func ctInverseBase(x g.Element) g.Element {
+	return ctExpBase(x, ctQ-2)
+}
+
+// Constant-time Tonelli-Shanks (RFC 9380, appendix I.4). Returns the square root
+// of x and whether it exists; the root is zero when it does not.
+func ctSqrtBase(x g.Element) (g.Element, uint64) {
+	// p - 1 = 2^32 * t with t = 2^32 - 1.
+	const s = g.TWO_ADICITY
+	const c3 = (ctQ - 1) >> (s + 1) // (t - 1) / 2
+
+	z := ctExpBase(x, c3)
+	t := ctMulBase(ctSquareBase(z), x)
+	z = ctMulBase(z, x)
+	b := t
+	c := g.FromUint64(uint64(g.POWER_OF_TWO_GENERATOR))
+	for k := s; k >= 2; k-- {
+		for j := 1; j <= k-2; j++ {
+			b = ctSquareBase(b)
+		}
+		e := ctEqualBase(b, ctOne)
+		z = ctSelectBase(e, ctMulBase(z, c), z)
+		c = ctSquareBase(c)
+		t = ctSelectBase(e, ctMulBase(t, c), t)
+		b = t
+	}
+
+	ok := ctEqualBase(ctSquareBase(z), x)
+	return ctSelectBase(ok, g.Element{}, z), ok
+}
+
+// If c == 0, return a0.
+// If c == 0xFFFFFFFFFFFFFFFF, return a1.
+// c MUST be equal to 0 or 0xFFFFFFFFFFFFFFFF.
+func Select(c uint64, a0, a1 Element) Element {
+	var res Element
+	for i := range res {
+		res[i] = ctSelectBase(c, a0[i], a1[i])
+	}
+	return res
+}
+
+func IsZeroCT(e Element) uint64 {
+	return ctIsZero64(e[0][0] | e[1][0] | e[2][0] | e[3][0] | e[4][0])
+}
+
+func EqualsCT(a, b Element) uint64 {
+	return ctIsZero64((a[0][0] ^ b[0][0]) | (a[1][0] ^ b[1][0]) | (a[2][0] ^ b[2][0]) | (a[3][0] ^ b[3][0]) | (a[4][0] ^ b[4][0]))
+}
+
+func AddCT(a, b Element) Element {
+	var res Element
+	for i := range res {
+		res[i] = ctAddBase(a[i], b[i])
+	}
+	return res
+}
+
+func SubCT(a, b Element) Element {
+	var res Element
+	for i := range res {
+		res[i] = ctSubBase(a[i], b[i])
+	}
+	return res
+}
+
+func NegCT(a Element) Element {
+	var res Element
+	for i := range res {
+		res[i] = ctNegBase(a[i])
+	}
+	return res
+}
+
+func ScalarMulCT(a Element, scalar g.Element) Element {
+	var res Element
+	for i := range res {
+		res[i] = ctMulBase(a[i], scalar)
+	}
+	return res
+}
+
+func MulCT(a, b Element) Element {
+	w := FP5_W
+
+	var res Element
+	for k := 0; k < 5; k++ {
+		// Terms landing on X^k directly, and on X^(k+5) = W*X^k.
+		var low, high g.Element
+		for i := 0; i <= k; i++ {
+			low = ctAddBase(low, ctMulBase(a[i], b[k-i]))
+		}
+		for i := k + 1; i < 5; i++ {
+			high = ctAddBase(high, ctMulBase(a[i], b[k+5-i]))
+		}
+		res[k] = ctAddBase(low, ctMulBase(w, high))
+	}
+	return res
+}
+
+func SquareCT(a Element) Element {
+	return MulCT(a, a)
+}
+
+func ExpPowerOf2CT(x Element, power int) Element {
+	res := x
+	for i := 0; i < power; i++ {
+		res = SquareCT(res)
+	}
+	return res
+}
+
+func RepeatedFrobeniusCT(x Element, count int) Element {
+	count %= FP5_D
+	z0 := ctOne
+	for i := 0; i < count; i++ {
+		z0 = ctMulBase(z0, FP5_DTH_ROOT)
+	}
+
+	var res Element
+	z := ctOne
+	for i := range res {
+		res[i] = ctMulBase(x[i], z)
+		z = ctMulBase(z, z0)
+	}
+	return res
+}
+
+func FrobeniusCT(x Element) Element {
+	return RepeatedFrobeniusCT(x, 1)
+}
+
+// Returns f such that x*f is the norm of x, together with the norm.
+func ctNormFactor(x Element) (Element, g.Element) {
+	d := FrobeniusCT(x)
+	e := MulCT(d, FrobeniusCT(d))
+	f := MulCT(e, RepeatedFrobeniusCT(e, 2))
+
+	// Only the constant term of x*f is non-zero.
+	var high g.Element
+	for i := 1; i < 5; i++ {
+		high = ctAddBase(high, ctMulBase(x[i], f[5-i]))
+	}
+	norm := ctAddBase(ctMulBase(x[0], f[0]), ctMulBase(FP5_W, high))
+	return f, norm
+}
+
+// Returns the inverse of a, or zero if a is zero, and whether a is invertible.
+func InverseOrZeroCT(a Element) (Element, uint64) {
+	f, norm := ctNormFactor(a)
+	return ScalarMulCT(f, ctInverseBase(norm)), ^IsZeroCT(a)
+}
+
+// Returns a/b and whether b is non-zero. The quotient is zero when b is zero.
+func DivCT(a, b Element) (Element, uint64) {
+	bInv, ok := InverseOrZeroCT(b)
+	return MulCT(a, bInv), ok
+}
+
+// Same as Legendre: 1 for non-zero squares, -1 for non-squares and 0 for zero.
+func LegendreCT(x Element) g.Element {
+	_, norm := ctNormFactor(x)
+	return ctExpBase(norm, (ctQ-1)/2)
+}
+
+// Returns a square root of x and whether it exists. The root is zero when it
+// does not. It may differ in sign from the one returned by Sqrt, CanonicalSqrtCT
+// and CanonicalSqrt agree.
+func SqrtCT(x Element) (Element, uint64) {
+	v := ExpPowerOf2CT(x, 31)
+	vInv, _ := InverseOrZeroCT(v)
+	d := MulCT(MulCT(x, ExpPowerOf2CT(v, 32)), vInv)
+	e := FrobeniusCT(MulCT(d, RepeatedFrobeniusCT(d, 2)))
+	f := SquareCT(e)
+
+	var high g.Element
+	for i := 1; i < 5; i++ {
+		high = ctAddBase(high, ctMulBase(x[i], f[5-i]))
+	}
+	norm := ctAddBase(ctMulBase(x[0], f[0]), ctMulBase(FP5_W, high))
+
+	s, ok := ctSqrtBase(norm)
+	eInv, _ := InverseOrZeroCT(e)
+	return Select(ok, FP5_ZERO, ScalarMulCT(eInv, s)), ok
+}
+
+func Sgn0CT(x Element) uint64 {
+	sign := uint64(0)
+	zero := uint64(0xFFFFFFFFFFFFFFFF)
+	for _, limb := range x {
+		canonical := ctMulBase(limb, g.Element{1}) // out of Montgomery form
+		signI := -((canonical[0] & 1) ^ 1)
+		sign |= zero & signI
+		zero &= ctIsZero64(canonical[0])
+	}
+	return sign
+}
+
+func CanonicalSqrtCT(x Element) (Element, uint64) {
+	r, ok := SqrtCT(x)
+	return Select(Sgn0CT(r), r, NegCT(r)), ok
+}
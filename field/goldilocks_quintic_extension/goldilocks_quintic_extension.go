@@ -2,9 +2,11 @@ package goldilocks_quintic_extension
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	"github.com/ppd0705/poseidon_crypto/internal/batch"
 )
 
 type Element [5]g.Element
@@ -94,6 +96,15 @@ func Sample() Element {
 	return Element{arr[0], arr[1], arr[2], arr[3], arr[4]}
 }
 
+// Samples a uniform element from the bytes of r, one coefficient at a time.
+func SampleFrom(r io.Reader) (Element, error) {
+	arr, err := g.RandArrayFrom(r, 5)
+	if err != nil {
+		return Element{}, err
+	}
+	return Element{arr[0], arr[1], arr[2], arr[3], arr[4]}, nil
+}
+
 func Equals(a, b Element) bool {
 	return a[0] == b[0] && a[1] == b[1] && a[2] == b[2] && a[3] == b[3] && a[4] == b[4]
 }
@@ -145,51 +156,9 @@ func Sub(a, b Element) Element {
 }
 
 func Mul(a, b Element) Element {
-	w := FP5_W
-
-	a0b0 := g.Mul(&a[0], &b[0])
-	a1b4 := g.Mul(&a[1], &b[4])
-	a2b3 := g.Mul(&a[2], &b[3])
-	a3b2 := g.Mul(&a[3], &b[2])
-	a4b1 := g.Mul(&a[4], &b[1])
-	added := g.Add(a1b4, a2b3, a3b2, a4b1)
-	muld := g.Mul(&w, &added)
-	c0 := g.Add(a0b0, muld)
-
-	a0b1 := g.Mul(&a[0], &b[1])
-	a1b0 := g.Mul(&a[1], &b[0])
-	a2b4 := g.Mul(&a[2], &b[4])
-	a3b3 := g.Mul(&a[3], &b[3])
-	a4b2 := g.Mul(&a[4], &b[2])
-	added = g.Add(a2b4, a3b3, a4b2)
-	muld = g.Mul(&w, &added)
-	c1 := g.Add(a0b1, a1b0, muld)
-
-	a0b2 := g.Mul(&a[0], &b[2])
-	a1b1 := g.Mul(&a[1], &b[1])
-	a2b0 := g.Mul(&a[2], &b[0])
-	a3b4 := g.Mul(&a[3], &b[4])
-	a4b3 := g.Mul(&a[4], &b[3])
-	added = g.Add(a3b4, a4b3)
-	muld = g.Mul(&w, &added)
-	c2 := g.Add(a0b2, a1b1, a2b0, muld)
-
-	a0b3 := g.Mul(&a[0], &b[3])
-	a1b2 := g.Mul(&a[1], &b[2])
-	a2b1 := g.Mul(&a[2], &b[1])
-	a3b0 := g.Mul(&a[3], &b[0])
-	a4b4 := g.Mul(&a[4], &b[4])
-	muld = g.Mul(&w, &a4b4)
-	c3 := g.Add(a0b3, a1b2, a2b1, a3b0, muld)
-
-	a0b4 := g.Mul(&a[0], &b[4])
-	a1b3 := g.Mul(&a[1], &b[3])
-	a2b2 := g.Mul(&a[2], &b[2])
-	a3b1 := g.Mul(&a[3], &b[1])
-	a4b0 := g.Mul(&a[4], &b[0])
-	c4 := g.Add(a0b4, a1b3, a2b2, a3b1, a4b0)
-
-	return Element{c0, c1, c2, c3, c4}
+	var res Element
+	res.Mul(&a, &b)
+	return res
 }
 
 func Div(a, b Element) Element {
@@ -209,39 +178,9 @@ func ExpPowerOf2(x Element, power int) Element {
 }
 
 func Square(a Element) Element {
-	w := FP5_W
-	double_w := g.Add(w, w)
-
-	a0s := g.Mul(&a[0], &a[0])
-	a1a4 := g.Mul(&a[1], &a[4])
-	a2a3 := g.Mul(&a[2], &a[3])
-	added := g.Add(a1a4, a2a3)
-	muld := g.Mul(&double_w, &added)
-	c0 := g.Add(a0s, muld)
-
-	a0Double := g.Add(a[0], a[0])
-	a0Doublea1 := g.Mul(&a0Double, &a[1])
-	a2a4DoubleW := g.Mul(&a[2], &a[4], &double_w)
-	a3a3w := g.Mul(&a[3], &a[3], &w)
-	c1 := g.Add(a0Doublea1, a2a4DoubleW, a3a3w)
-
-	a0Doublea2 := g.Mul(&a0Double, &a[2])
-	a1Square := g.Mul(&a[1], &a[1])
-	a4a3DoubleW := g.Mul(&a[4], &a[3], &double_w)
-	c2 := g.Add(a0Doublea2, a1Square, a4a3DoubleW)
-
-	a1Double := g.Add(a[1], a[1])
-	a0Doublea3 := g.Mul(&a0Double, &a[3])
-	a1Doublea2 := g.Mul(&a1Double, &a[2])
-	a4SquareW := g.Mul(&a[4], &a[4], &w)
-	c3 := g.Add(a0Doublea3, a1Doublea2, a4SquareW)
-
-	a0Doublea4 := g.Mul(&a0Double, &a[4])
-	a1Doublea3 := g.Mul(&a1Double, &a[3])
-	a2Square := g.Mul(&a[2], &a[2])
-	c4 := g.Add(a0Doublea4, a1Doublea3, a2Square)
-
-	return Element{c0, c1, c2, c3, c4}
+	var res Element
+	res.Square(&a)
+	return res
 }
 
 func Triple(a Element) Element {
@@ -324,20 +263,117 @@ func InverseOrZero(a Element) Element {
 		return FP5_ZERO
 	}
 
-	d := Frobenius(a)
+	f, norm := normFactor(a)
+	var normInv g.Element
+	normInv.Inverse(&norm)
+	return ScalarMul(f, normInv)
+}
+
+// Returns the product f of the conjugates of x other than x itself, and the norm
+// x*f, which lies in the base field.
+func normFactor(x Element) (Element, g.Element) {
+	d := Frobenius(x)
 	e := Mul(d, Frobenius(d))
 	f := Mul(e, RepeatedFrobenius(e, 2))
 
-	a0b0 := g.Mul(&a[0], &f[0])
-	a1b4 := g.Mul(&a[1], &f[4])
-	a2b3 := g.Mul(&a[2], &f[3])
-	a3b2 := g.Mul(&a[3], &f[2])
-	a4b1 := g.Mul(&a[4], &f[1])
-	added := g.Add(a1b4, a2b3, a3b2, a4b1)
+	x0f0 := g.Mul(&x[0], &f[0])
+	x1f4 := g.Mul(&x[1], &f[4])
+	x2f3 := g.Mul(&x[2], &f[3])
+	x3f2 := g.Mul(&x[3], &f[2])
+	x4f1 := g.Mul(&x[4], &f[1])
+	added := g.Add(x1f4, x2f3, x3f2, x4f1)
 	muld := g.Mul(&FP5_W, &added)
-	g := g.Add(a0b0, muld)
+	return f, g.Add(x0f0, muld)
+}
+
+// Norm of x down to the base field: the product of its five conjugates.
+func Norm(x Element) g.Element {
+	_, norm := normFactor(x)
+	return norm
+}
+
+// Trace of x down to the base field: the sum of its five conjugates. The
+// conjugates of X^i sum to zero for 0 < i < 5, which leaves 5*x[0].
+func Trace(x Element) g.Element {
+	five := g.FromUint64(5)
+	return g.Mul(&x[0], &five)
+}
+
+// Returns true if x lies in the base field, i.e. x = Frobenius(x).
+func IsInBaseField(x Element) bool {
+	return x[1].IsZero() && x[2].IsZero() && x[3].IsZero() && x[4].IsZero()
+}
+
+// Returns x^power. A negative power raises the inverse of x to -power, and
+// panics if x is zero.
+func Exp(x Element, power *big.Int) Element {
+	if power.Sign() < 0 {
+		x = InverseOrZero(x)
+		if IsZero(x) {
+			panic("zero raised to a negative power")
+		}
+		power = new(big.Int).Neg(power)
+	}
 
-	return ScalarMul(f, *g.Inverse(&g))
+	res := FP5_ONE
+	for i := power.BitLen() - 1; i >= 0; i-- {
+		res = Square(res)
+		if power.Bit(i) == 1 {
+			res = Mul(res, x)
+		}
+	}
+	return res
+}
+
+func ExpUint64(x Element, power uint64) Element {
+	return Exp(x, new(big.Int).SetUint64(power))
+}
+
+// Coefficients of the minimal polynomial of x over the base field, from the
+// constant term up to the leading 1. Since 5 is prime there are no intermediate
+// subfields: the degree is 1 for base field elements and 5 otherwise, in which
+// case the polynomial is the product of (X - c) over the conjugates c of x.
+func MinimalPolynomial(x Element) []g.Element {
+	if IsInBaseField(x) {
+		return []g.Element{g.Neg(x[0]), g.One()}
+	}
+
+	// Coefficients in the extension, they all end up in the base field.
+	coeffs := []Element{FP5_ONE}
+	conjugate := x
+	for k := 0; k < FP5_D; k++ {
+		next := make([]Element, len(coeffs)+1)
+		for i, c := range coeffs {
+			next[i+1] = Add(next[i+1], c)
+			next[i] = Sub(next[i], Mul(c, conjugate))
+		}
+		coeffs = next
+		conjugate = Frobenius(conjugate)
+	}
+
+	res := make([]g.Element, len(coeffs))
+	for i, c := range coeffs {
+		res[i] = c[0]
+	}
+	return res
+}
+
+// Slices shorter than this are inverted on the calling goroutine.
+const batchInverseMinChunk = 1 << 10
+
+// Inverts every element of the slice using Montgomery's trick. Zero elements are
+// skipped and map to zero in the result.
+func BatchInverse(in []Element) []Element {
+	res := make([]Element, len(in))
+	batch.ParallelChunks(len(in), batchInverseMinChunk, func(start, end int) {
+		batch.InverseChunk(in[start:end], res[start:end],
+			func(e *Element) bool { return IsZero(*e) },
+			func(a, b *Element) Element { return Mul(*a, *b) },
+			func(e *Element) Element { return InverseOrZero(*e) },
+			FP5_ONE,
+		)
+	})
+	return res
 }
 
 func Frobenius(x Element) Element {
@@ -364,14 +400,7 @@ func RepeatedFrobenius(x Element, count int) Element {
 }
 
 func Legendre(x Element) g.Element {
-	frob1 := Frobenius(x)
-	frob2 := Frobenius(frob1)
-
-	frob1TimesFrob2 := Mul(frob1, frob2)
-	frob2Frob1TimesFrob2 := RepeatedFrobenius(frob1TimesFrob2, 2)
-
-	xrExt := Mul(Mul(x, frob1TimesFrob2), frob2Frob1TimesFrob2)
-	xr := g.FromUint64(xrExt[0].Uint64())
+	xr := Norm(x)
 
 	xr31 := xr.Exp(xr, new(big.Int).SetUint64(1<<31))
 	xr31InvOrZero := g.FromUint64(0)
@@ -0,0 +1,21 @@
+package goldilocks_quintic_extension
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// Hashes msg to count uniformly distributed extension elements, following
+// hash_to_field of RFC 9380 with extension degree m = 5: the coefficients of
+// the i-th element are base field elements 5i to 5i+4 of g.HashToField. At most
+// 68 elements can be produced by a single call.
+func HashToField(msg, dst []byte, count int) ([]Element, error) {
+	coeffs, err := g.HashToField(msg, dst, 5*count)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Element, count)
+	for i := range res {
+		copy(res[i][:], coeffs[5*i:5*i+5])
+	}
+	return res, nil
+}
@@ -0,0 +1,48 @@
+package goldilocks_quintic_extension
+
+import (
+	"github.com/ppd0705/poseidon_crypto/internal/marshal"
+)
+
+// The binary form of an Element is the concatenation of the canonical 8-byte
+// little-endian encodings of its limbs, the text form is the hex of the binary
+// one and the JSON form is the text as a string. Decoding rejects non-canonical
+// limbs.
+
+func (e Element) MarshalBinary() ([]byte, error) {
+	return e.ToLittleEndianBytes(), nil
+}
+
+func (e *Element) UnmarshalBinary(data []byte) error {
+	elem, err := FromCanonicalLittleEndianBytes(data)
+	if err != nil {
+		return err
+	}
+	*e = elem
+	return nil
+}
+
+func (e Element) MarshalText() ([]byte, error) {
+	return marshal.EncodeHex(e.ToLittleEndianBytes()), nil
+}
+
+func (e *Element) UnmarshalText(text []byte) error {
+	data, err := marshal.DecodeHex(text, Bytes)
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalBinary(data)
+}
+
+func (e Element) MarshalJSON() ([]byte, error) {
+	text, _ := e.MarshalText()
+	return marshal.MarshalJSONText(text)
+}
+
+func (e *Element) UnmarshalJSON(data []byte) error {
+	text, err := marshal.UnmarshalJSONText(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return e.UnmarshalText(text)
+}
@@ -0,0 +1,375 @@
+// Package goldilocks_quintic_extension_plonky2 is the quintic extension
+// F[X]/(X^5 - 3) built on GoldilocksField. It mirrors the API of
+// goldilocks_quintic_extension, which is built on gnark's Montgomery elements,
+// and returns the same values.
+package goldilocks_quintic_extension_plonky2
+
+import (
+	"fmt"
+	"io"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+	"github.com/ppd0705/poseidon_crypto/internal/batch"
+)
+
+type Element [5]g.GoldilocksField
+
+const Bytes = g.Bytes * 5
+
+var (
+	FP5_D = 5
+
+	FP5_ZERO = Element{}
+	FP5_ONE  = Element{g.OneF()}
+	FP5_TWO  = FromUint64(2)
+
+	FP5_W        = g.GoldilocksField(3)
+	FP5_DTH_ROOT = g.GoldilocksField(1041288259238279555)
+)
+
+func (e *Element) ToString() string {
+	return fmt.Sprintf("%d,%d,%d,%d,%d", e[0].ToCanonicalUint64(), e[1].ToCanonicalUint64(), e[2].ToCanonicalUint64(), e[3].ToCanonicalUint64(), e[4].ToCanonicalUint64())
+}
+
+func (e Element) ToUint64Array() [5]uint64 {
+	return [5]uint64{e[0].ToCanonicalUint64(), e[1].ToCanonicalUint64(), e[2].ToCanonicalUint64(), e[3].ToCanonicalUint64(), e[4].ToCanonicalUint64()}
+}
+
+func (e Element) ToLittleEndianBytes() []byte {
+	elemBytes := [Bytes]byte{}
+	for i, limb := range e {
+		copy(elemBytes[i*g.Bytes:], g.ToLittleEndianBytesF(limb))
+	}
+	return elemBytes[:]
+}
+
+func FromCanonicalLittleEndianBytes(in []byte) (Element, error) {
+	if len(in) != Bytes {
+		return Element{}, fmt.Errorf("input bytes len should be 40 but is %d", len(in))
+	}
+
+	limbs, err := g.ArrayFromCanonicalLittleEndianBytesF(in)
+	if err != nil {
+		return Element{}, fmt.Errorf("failed to convert bytes to field element: %w", err)
+	}
+	return Element{limbs[0], limbs[1], limbs[2], limbs[3], limbs[4]}, nil
+}
+
+// Conversion from the gnark-based representation.
+func FromGFp5(e gFp5.Element) Element {
+	return Element{
+		g.GoldilocksField(e[0].Uint64()),
+		g.GoldilocksField(e[1].Uint64()),
+		g.GoldilocksField(e[2].Uint64()),
+		g.GoldilocksField(e[3].Uint64()),
+		g.GoldilocksField(e[4].Uint64()),
+	}
+}
+
+// Conversion to the gnark-based representation.
+func (e Element) ToGFp5() gFp5.Element {
+	return gFp5.FromUint64Array(e.ToUint64Array())
+}
+
+func Sample() Element {
+	return FromGFp5(gFp5.Sample())
+}
+
+// Samples a uniform element from the bytes of r. Consumes r exactly like
+// gFp5.SampleFrom, so both return the same value for the same bytes.
+func SampleFrom(r io.Reader) (Element, error) {
+	var res Element
+	for i := range res {
+		x, err := g.SampleFFrom(r)
+		if err != nil {
+			return Element{}, err
+		}
+		res[i] = x
+	}
+	return res, nil
+}
+
+func Equals(a, b Element) bool {
+	return g.EqualsF(a[0], b[0]) && g.EqualsF(a[1], b[1]) && g.EqualsF(a[2], b[2]) && g.EqualsF(a[3], b[3]) && g.EqualsF(a[4], b[4])
+}
+
+func IsZero(e Element) bool {
+	return e[0].IsZero() && e[1].IsZero() && e[2].IsZero() && e[3].IsZero() && e[4].IsZero()
+}
+
+func FromF(elem g.GoldilocksField) Element {
+	return Element{elem}
+}
+
+func FromUint64(a uint64) Element {
+	return Element{g.GoldilocksField(a % g.ORDER)}
+}
+
+func FromUint64Array(elems [5]uint64) Element {
+	return Element{
+		g.GoldilocksField(elems[0] % g.ORDER),
+		g.GoldilocksField(elems[1] % g.ORDER),
+		g.GoldilocksField(elems[2] % g.ORDER),
+		g.GoldilocksField(elems[3] % g.ORDER),
+		g.GoldilocksField(elems[4] % g.ORDER),
+	}
+}
+
+func Neg(e Element) Element {
+	return Element{g.NegF(e[0]), g.NegF(e[1]), g.NegF(e[2]), g.NegF(e[3]), g.NegF(e[4])}
+}
+
+func Add(a, b Element) Element {
+	return Element{
+		g.AddF(a[0], b[0]),
+		g.AddF(a[1], b[1]),
+		g.AddF(a[2], b[2]),
+		g.AddF(a[3], b[3]),
+		g.AddF(a[4], b[4]),
+	}
+}
+
+func Sub(a, b Element) Element {
+	return Element{
+		g.SubF(a[0], b[0]),
+		g.SubF(a[1], b[1]),
+		g.SubF(a[2], b[2]),
+		g.SubF(a[3], b[3]),
+		g.SubF(a[4], b[4]),
+	}
+}
+
+// The products landing on each coefficient are summed in a g.AccF and reduced
+// once, with W folded into the first operand of the wrapping products.
+func Mul(a, b Element) Element {
+	var wa [5]g.GoldilocksField
+	for i := 1; i < 5; i++ {
+		wa[i] = g.MulF(FP5_W, a[i])
+	}
+
+	var c0, c1, c2, c3, c4 g.AccF
+	c0.MulAdd(a[0], b[0])
+	c0.MulAdd(wa[1], b[4])
+	c0.MulAdd(wa[2], b[3])
+	c0.MulAdd(wa[3], b[2])
+	c0.MulAdd(wa[4], b[1])
+
+	c1.MulAdd(a[0], b[1])
+	c1.MulAdd(a[1], b[0])
+	c1.MulAdd(wa[2], b[4])
+	c1.MulAdd(wa[3], b[3])
+	c1.MulAdd(wa[4], b[2])
+
+	c2.MulAdd(a[0], b[2])
+	c2.MulAdd(a[1], b[1])
+	c2.MulAdd(a[2], b[0])
+	c2.MulAdd(wa[3], b[4])
+	c2.MulAdd(wa[4], b[3])
+
+	c3.MulAdd(a[0], b[3])
+	c3.MulAdd(a[1], b[2])
+	c3.MulAdd(a[2], b[1])
+	c3.MulAdd(a[3], b[0])
+	c3.MulAdd(wa[4], b[4])
+
+	c4.MulAdd(a[0], b[4])
+	c4.MulAdd(a[1], b[3])
+	c4.MulAdd(a[2], b[2])
+	c4.MulAdd(a[3], b[1])
+	c4.MulAdd(a[4], b[0])
+
+	return Element{c0.Reduce(), c1.Reduce(), c2.Reduce(), c3.Reduce(), c4.Reduce()}
+}
+
+func Div(a, b Element) Element {
+	bInv := InverseOrZero(b)
+	if IsZero(bInv) {
+		panic("division by zero")
+	}
+	return Mul(a, bInv)
+}
+
+func ExpPowerOf2(x Element, power int) Element {
+	res := x
+	for i := 0; i < power; i++ {
+		res = Square(res)
+	}
+	return res
+}
+
+// Symmetric products are computed once with a doubled operand: 15 products
+// instead of 25.
+func Square(a Element) Element {
+	d0 := g.DoubleF(a[0])
+	d1 := g.DoubleF(a[1])
+	w3 := g.MulF(FP5_W, a[3])
+	w4 := g.MulF(FP5_W, a[4])
+	w6a1 := g.DoubleF(g.MulF(FP5_W, a[1]))
+	w6a2 := g.DoubleF(g.MulF(FP5_W, a[2]))
+	w6a3 := g.DoubleF(w3)
+
+	var c0, c1, c2, c3, c4 g.AccF
+	c0.MulAdd(a[0], a[0])
+	c0.MulAdd(w6a1, a[4])
+	c0.MulAdd(w6a2, a[3])
+
+	c1.MulAdd(d0, a[1])
+	c1.MulAdd(w6a2, a[4])
+	c1.MulAdd(w3, a[3])
+
+	c2.MulAdd(d0, a[2])
+	c2.MulAdd(a[1], a[1])
+	c2.MulAdd(w6a3, a[4])
+
+	c3.MulAdd(d0, a[3])
+	c3.MulAdd(d1, a[2])
+	c3.MulAdd(w4, a[4])
+
+	c4.MulAdd(d0, a[4])
+	c4.MulAdd(d1, a[3])
+	c4.MulAdd(a[2], a[2])
+
+	return Element{c0.Reduce(), c1.Reduce(), c2.Reduce(), c3.Reduce(), c4.Reduce()}
+}
+
+func Triple(a Element) Element {
+	three := g.GoldilocksField(3)
+	return ScalarMul(a, three)
+}
+
+func Sqrt(x Element) (Element, bool) {
+	v := ExpPowerOf2(x, 31)
+	d := Mul(Mul(x, ExpPowerOf2(v, 32)), InverseOrZero(v))
+	e := Frobenius(Mul(d, RepeatedFrobenius(d, 2)))
+	_f := Square(e)
+
+	x1f4 := g.MulF(x[1], _f[4])
+	x2f3 := g.MulF(x[2], _f[3])
+	x3f2 := g.MulF(x[3], _f[2])
+	x4f1 := g.MulF(x[4], _f[1])
+	added := g.AddF(g.AddF(x1f4, x2f3), g.AddF(x3f2, x4f1))
+	x0f0 := g.MulF(x[0], _f[0])
+	_g := g.AddF(x0f0, g.MulF(FP5_W, added))
+
+	// SqrtF picks the same root as gnark's Sqrt, which the gnark-based
+	// implementation relies on.
+	s, ok := g.SqrtF(_g)
+	if !ok {
+		return Element{}, false
+	}
+
+	return ScalarMul(InverseOrZero(e), s), true
+}
+
+func Sgn0(x Element) bool {
+	sign := false
+	zero := true
+	for _, limb := range x {
+		sign_i := (limb.ToCanonicalUint64() & 1) == 0
+		zero_i := limb.IsZero()
+		sign = sign || (zero && sign_i)
+		zero = zero && zero_i
+	}
+	return sign
+}
+
+func CanonicalSqrt(x Element) (Element, bool) {
+	sqrtX, exists := Sqrt(x)
+	if !exists {
+		return Element{}, false
+	}
+
+	if Sgn0(sqrtX) {
+		return Neg(sqrtX), true
+	}
+	return sqrtX, true
+}
+
+func ScalarMul(a Element, scalar g.GoldilocksField) Element {
+	return Element{
+		g.MulF(a[0], scalar),
+		g.MulF(a[1], scalar),
+		g.MulF(a[2], scalar),
+		g.MulF(a[3], scalar),
+		g.MulF(a[4], scalar),
+	}
+}
+
+func Double(a Element) Element {
+	return Add(a, a)
+}
+
+func InverseOrZero(a Element) Element {
+	if IsZero(a) {
+		return FP5_ZERO
+	}
+
+	d := Frobenius(a)
+	e := Mul(d, Frobenius(d))
+	f := Mul(e, RepeatedFrobenius(e, 2))
+
+	a0b0 := g.MulF(a[0], f[0])
+	a1b4 := g.MulF(a[1], f[4])
+	a2b3 := g.MulF(a[2], f[3])
+	a3b2 := g.MulF(a[3], f[2])
+	a4b1 := g.MulF(a[4], f[1])
+	added := g.AddF(g.AddF(a1b4, a2b3), g.AddF(a3b2, a4b1))
+	norm := g.AddF(a0b0, g.MulF(FP5_W, added))
+
+	return ScalarMul(f, g.InverseF(norm))
+}
+
+// Slices shorter than this are inverted on the calling goroutine.
+const batchInverseMinChunk = 1 << 10
+
+// Inverts every element of the slice using Montgomery's trick. Zero elements are
+// skipped and map to zero in the result.
+func BatchInverse(in []Element) []Element {
+	res := make([]Element, len(in))
+	batch.ParallelChunks(len(in), batchInverseMinChunk, func(start, end int) {
+		batch.InverseChunk(in[start:end], res[start:end],
+			func(e *Element) bool { return IsZero(*e) },
+			func(a, b *Element) Element { return Mul(*a, *b) },
+			func(e *Element) Element { return InverseOrZero(*e) },
+			FP5_ONE,
+		)
+	})
+	return res
+}
+
+func Frobenius(x Element) Element {
+	return RepeatedFrobenius(x, 1)
+}
+
+func RepeatedFrobenius(x Element, count int) Element {
+	if count == 0 {
+		return x
+	} else if count >= FP5_D {
+		return RepeatedFrobenius(x, count%FP5_D)
+	}
+
+	z0 := FP5_DTH_ROOT
+	for i := 1; i < count; i++ {
+		z0 = g.MulF(FP5_DTH_ROOT, z0)
+	}
+
+	res := Element{}
+	for i, z := range g.PowersF(z0, FP5_D) {
+		res[i] = g.MulF(x[i], z)
+	}
+	return res
+}
+
+// Returns 1 if x is a non-zero square, p-1 if it is a non-square and 0 if x is
+// zero. x is a square if and only if its norm is a square in the base field.
+func Legendre(x Element) g.GoldilocksField {
+	frob1 := Frobenius(x)
+	frob2 := Frobenius(frob1)
+
+	frob1TimesFrob2 := Mul(frob1, frob2)
+	frob2Frob1TimesFrob2 := RepeatedFrobenius(frob1TimesFrob2, 2)
+
+	xrExt := Mul(Mul(x, frob1TimesFrob2), frob2Frob1TimesFrob2)
+	return g.LegendreF(xrExt[0])
+}
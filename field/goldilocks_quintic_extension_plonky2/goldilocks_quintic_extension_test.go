@@ -0,0 +1,180 @@
+package goldilocks_quintic_extension_plonky2
+
+import (
+	"runtime"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+
+	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+)
+
+// Every operation must return the same canonical values as the gnark-based
+// implementation.
+
+func checkSame(t *testing.T, name string, got Element, want gFp5.Element) {
+	t.Helper()
+	if got.ToUint64Array() != want.ToUint64Array() {
+		t.Fatalf("%s: expected %v, got %v", name, want.ToUint64Array(), got.ToUint64Array())
+	}
+}
+
+func TestMatchesGnarkImplementation(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a, b := gFp5.Sample(), gFp5.Sample()
+		x, y := FromGFp5(a), FromGFp5(b)
+
+		checkSame(t, "Add", Add(x, y), gFp5.Add(a, b))
+		checkSame(t, "Sub", Sub(x, y), gFp5.Sub(a, b))
+		checkSame(t, "Neg", Neg(x), gFp5.Neg(a))
+		checkSame(t, "Mul", Mul(x, y), gFp5.Mul(a, b))
+		checkSame(t, "Square", Square(x), gFp5.Square(a))
+		checkSame(t, "Triple", Triple(x), gFp5.Triple(a))
+		checkSame(t, "Div", Div(x, y), gFp5.Div(a, b))
+		checkSame(t, "ExpPowerOf2", ExpPowerOf2(x, 7), gFp5.ExpPowerOf2(a, 7))
+		checkSame(t, "InverseOrZero", InverseOrZero(x), gFp5.InverseOrZero(a))
+		checkSame(t, "ScalarMul", ScalarMul(x, y[2]), gFp5.ScalarMul(a, b[2]))
+		for k := 0; k < 6; k++ {
+			checkSame(t, "RepeatedFrobenius", RepeatedFrobenius(x, k), gFp5.RepeatedFrobenius(a, k))
+		}
+
+		legendre := gFp5.Legendre(a)
+		if Legendre(x).ToCanonicalUint64() != legendre.Uint64() {
+			t.Fatalf("Legendre: expected %d, got %d", legendre.Uint64(), Legendre(x).ToCanonicalUint64())
+		}
+
+		for _, v := range []gFp5.Element{a, gFp5.Square(a)} {
+			got, ok := Sqrt(FromGFp5(v))
+			want, wantOk := gFp5.Sqrt(v)
+			if ok != wantOk {
+				t.Fatalf("Sqrt: expected existence %v, got %v", wantOk, ok)
+			}
+			checkSame(t, "Sqrt", got, want)
+
+			got, ok = CanonicalSqrt(FromGFp5(v))
+			want, _ = gFp5.CanonicalSqrt(v)
+			if ok != wantOk {
+				t.Fatalf("CanonicalSqrt: expected existence %v, got %v", wantOk, ok)
+			}
+			checkSame(t, "CanonicalSqrt", got, want)
+			if Sgn0(FromGFp5(v)) != gFp5.Sgn0(v) {
+				t.Fatalf("Sgn0 mismatch")
+			}
+		}
+
+		if !gFp5.Equals(x.ToGFp5(), a) {
+			t.Fatalf("ToGFp5 is not the inverse of FromGFp5")
+		}
+		if got, err := FromCanonicalLittleEndianBytes(a.ToLittleEndianBytes()); err != nil || !Equals(got, x) {
+			t.Fatalf("Failed to decode the gnark-based encoding: %v", err)
+		}
+	}
+
+	checkSame(t, "InverseOrZero(0)", InverseOrZero(FP5_ZERO), gFp5.FP5_ZERO)
+	if Legendre(FP5_ZERO) != 0 {
+		t.Fatalf("Expected Legendre(0) = 0")
+	}
+}
+
+func TestBatchInverse(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, n := range []int{0, 1, 5, 3 * batchInverseMinChunk} {
+		in := make([]Element, n)
+		for i := range in {
+			if i%7 != 3 {
+				in[i] = Sample()
+			}
+		}
+
+		res := BatchInverse(in)
+		for i := range in {
+			if !Equals(res[i], InverseOrZero(in[i])) {
+				t.Fatalf("Batch inverse of size %d mismatch at index %d", n, i)
+			}
+		}
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	x, y := Sample(), Sample()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x = Mul(x, y)
+	}
+}
+
+func BenchmarkMulGnark(b *testing.B) {
+	x, y := gFp5.Sample(), gFp5.Sample()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x = gFp5.Mul(x, y)
+	}
+}
+
+func BenchmarkSquare(b *testing.B) {
+	x := Sample()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x = Square(x)
+	}
+}
+
+func BenchmarkSquareGnark(b *testing.B) {
+	x := gFp5.Sample()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x = gFp5.Square(x)
+	}
+}
+
+func BenchmarkInverseOrZero(b *testing.B) {
+	x := Sample()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x = InverseOrZero(x)
+	}
+}
+
+func BenchmarkInverseOrZeroGnark(b *testing.B) {
+	x := gFp5.Sample()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x = gFp5.InverseOrZero(x)
+	}
+}
+
+func BenchmarkSqrt(b *testing.B) {
+	x := Square(Sample())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Sqrt(x)
+	}
+}
+
+func BenchmarkSqrtGnark(b *testing.B) {
+	x := gFp5.Square(gFp5.Sample())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gFp5.Sqrt(x)
+	}
+}
+
+func TestSampleFrom(t *testing.T) {
+	for _, seed := range []string{"", "seed"} {
+		xof := sha3.NewShake256()
+		xof.Write([]byte(seed))
+		gnarkXof := sha3.NewShake256()
+		gnarkXof.Write([]byte(seed))
+
+		x, err := SampleFrom(xof)
+		if err != nil {
+			t.Fatalf("SampleFrom failed: %v", err)
+		}
+		want, err := gFp5.SampleFrom(gnarkXof)
+		if err != nil {
+			t.Fatalf("gFp5.SampleFrom failed: %v", err)
+		}
+		checkSame(t, "SampleFrom", x, want)
+	}
+}
@@ -0,0 +1,500 @@
+// Package matrix implements dense linear algebra over the Goldilocks field,
+// along with the checks used to validate the linear layers of Poseidon-style
+// permutations.
+package matrix
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	"github.com/ppd0705/poseidon_crypto/field/poly"
+)
+
+var ErrSingular = errors.New("matrix is singular")
+
+// Dense matrix stored row by row. All rows must have the same length.
+type Matrix [][]g.GoldilocksField
+
+func New(rows, cols int) Matrix {
+	m := make(Matrix, rows)
+	for i := range m {
+		m[i] = make([]g.GoldilocksField, cols)
+	}
+	return m
+}
+
+func Identity(n int) Matrix {
+	m := New(n, n)
+	for i := range m {
+		m[i][i] = g.OneF()
+	}
+	return m
+}
+
+func FromUint64(rows [][]uint64) Matrix {
+	m := make(Matrix, len(rows))
+	for i, row := range rows {
+		m[i] = make([]g.GoldilocksField, len(row))
+		for j, x := range row {
+			m[i][j] = g.GoldilocksField(x % g.ORDER)
+		}
+	}
+	return m
+}
+
+// Square matrix whose row i is firstRow rotated right by i.
+func Circulant(firstRow []g.GoldilocksField) Matrix {
+	n := len(firstRow)
+	m := New(n, n)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = firstRow[(j-i+n)%n]
+		}
+	}
+	return m
+}
+
+func Diagonal(diag []g.GoldilocksField) Matrix {
+	m := New(len(diag), len(diag))
+	for i := range diag {
+		m[i][i] = diag[i]
+	}
+	return m
+}
+
+// Matrix of a linear map on vectors of length n, recovered from the images of
+// the unit vectors. f may modify its argument in place.
+func FromLinearMap(n int, f func(v []g.GoldilocksField) []g.GoldilocksField) Matrix {
+	var m Matrix
+	for j := 0; j < n; j++ {
+		v := make([]g.GoldilocksField, n)
+		v[j] = g.OneF()
+		col := f(v)
+		if m == nil {
+			m = New(len(col), n)
+		}
+		for i := range col {
+			m[i][j] = col[i]
+		}
+	}
+	return m
+}
+
+func (m Matrix) Rows() int {
+	return len(m)
+}
+
+func (m Matrix) Cols() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+func (m Matrix) IsSquare() bool {
+	return m.Rows() == m.Cols()
+}
+
+func (m Matrix) Clone() Matrix {
+	res := make(Matrix, len(m))
+	for i := range m {
+		res[i] = append([]g.GoldilocksField(nil), m[i]...)
+	}
+	return res
+}
+
+func (m Matrix) Equals(n Matrix) bool {
+	if m.Rows() != n.Rows() || m.Cols() != n.Cols() {
+		return false
+	}
+	for i := range m {
+		for j := range m[i] {
+			if !g.EqualsF(m[i][j], n[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (m Matrix) Transpose() Matrix {
+	res := New(m.Cols(), m.Rows())
+	for i := range m {
+		for j := range m[i] {
+			res[j][i] = m[i][j]
+		}
+	}
+	return res
+}
+
+func (m Matrix) Add(n Matrix) Matrix {
+	checkSameShape(m, n)
+	res := New(m.Rows(), m.Cols())
+	for i := range m {
+		for j := range m[i] {
+			res[i][j] = g.AddF(m[i][j], n[i][j])
+		}
+	}
+	return res
+}
+
+func (m Matrix) Sub(n Matrix) Matrix {
+	checkSameShape(m, n)
+	res := New(m.Rows(), m.Cols())
+	for i := range m {
+		for j := range m[i] {
+			res[i][j] = g.SubF(m[i][j], n[i][j])
+		}
+	}
+	return res
+}
+
+func (m Matrix) ScalarMul(c g.GoldilocksField) Matrix {
+	res := New(m.Rows(), m.Cols())
+	for i := range m {
+		for j := range m[i] {
+			res[i][j] = g.MulF(m[i][j], c)
+		}
+	}
+	return res
+}
+
+func checkSameShape(m, n Matrix) {
+	if m.Rows() != n.Rows() || m.Cols() != n.Cols() {
+		panic(fmt.Sprintf("matrices are %dx%d and %dx%d", m.Rows(), m.Cols(), n.Rows(), n.Cols()))
+	}
+}
+
+func (m Matrix) Mul(n Matrix) Matrix {
+	if m.Cols() != n.Rows() {
+		panic(fmt.Sprintf("cannot multiply %dx%d and %dx%d matrices", m.Rows(), m.Cols(), n.Rows(), n.Cols()))
+	}
+	res := New(m.Rows(), n.Cols())
+	accs := make([]g.AccF, n.Cols())
+	for i := range m {
+		for k, a := range m[i] {
+			for j, b := range n[k] {
+				accs[j].MulAdd(a, b)
+			}
+		}
+		for j := range accs {
+			res[i][j] = accs[j].Reduce()
+			accs[j] = g.AccF{}
+		}
+	}
+	return res
+}
+
+func (m Matrix) MulVec(v []g.GoldilocksField) []g.GoldilocksField {
+	if m.Cols() != len(v) {
+		panic(fmt.Sprintf("cannot multiply a %dx%d matrix by a vector of length %d", m.Rows(), m.Cols(), len(v)))
+	}
+	res := make([]g.GoldilocksField, m.Rows())
+	for i := range m {
+		res[i] = g.InnerProduct(m[i], v)
+	}
+	return res
+}
+
+// Returns m^e for a square matrix, the identity for e = 0.
+func (m Matrix) Pow(e uint64) Matrix {
+	if !m.IsSquare() {
+		panic(fmt.Sprintf("cannot raise a %dx%d matrix to a power", m.Rows(), m.Cols()))
+	}
+	res, base := Identity(m.Rows()), m
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = res.Mul(base)
+		}
+		if e > 1 {
+			base = base.Mul(base)
+		}
+	}
+	return res
+}
+
+// Gauss-Jordan elimination. Returns the reduced row echelon form of m and the
+// columns of its pivots, whose number is the rank of m.
+func (m Matrix) RowEchelon() (Matrix, []int) {
+	res := m.Clone()
+	pivots, _ := res.eliminate()
+	return res, pivots
+}
+
+// Reduces m to reduced row echelon form in place. Returns the pivot columns and
+// the determinant of the row operations performed, i.e. det(m) = det / det(ops)
+// for square m.
+func (m Matrix) eliminate() ([]int, g.GoldilocksField) {
+	var pivots []int
+	opsDet := g.OneF()
+	row := 0
+	for col := 0; col < m.Cols() && row < m.Rows(); col++ {
+		pivot := -1
+		for i := row; i < m.Rows(); i++ {
+			if !m[i][col].IsZero() {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		if pivot != row {
+			m[pivot], m[row] = m[row], m[pivot]
+			opsDet = g.NegF(opsDet)
+		}
+
+		inv := g.InverseF(m[row][col])
+		opsDet = g.MulF(opsDet, inv)
+		for j := col; j < m.Cols(); j++ {
+			m[row][j] = g.MulF(m[row][j], inv)
+		}
+		for i := range m {
+			if i == row || m[i][col].IsZero() {
+				continue
+			}
+			c := m[i][col]
+			for j := col; j < m.Cols(); j++ {
+				m[i][j] = g.SubF(m[i][j], g.MulF(c, m[row][j]))
+			}
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+	return pivots, opsDet
+}
+
+func (m Matrix) Rank() int {
+	_, pivots := m.RowEchelon()
+	return len(pivots)
+}
+
+func (m Matrix) Determinant() g.GoldilocksField {
+	if !m.IsSquare() {
+		panic(fmt.Sprintf("cannot take the determinant of a %dx%d matrix", m.Rows(), m.Cols()))
+	}
+	pivots, opsDet := m.Clone().eliminate()
+	if len(pivots) < m.Rows() {
+		return g.ZeroF()
+	}
+	// The reduced form is the identity.
+	return g.InverseF(opsDet)
+}
+
+func (m Matrix) IsInvertible() bool {
+	return m.IsSquare() && m.Rank() == m.Rows()
+}
+
+func (m Matrix) Inverse() (Matrix, error) {
+	if !m.IsSquare() {
+		return nil, fmt.Errorf("cannot invert a %dx%d matrix", m.Rows(), m.Cols())
+	}
+	n := m.Rows()
+	augmented := New(n, 2*n)
+	for i := range m {
+		copy(augmented[i], m[i])
+		augmented[i][n+i] = g.OneF()
+	}
+	if pivots, _ := augmented.eliminate(); len(pivots) < n || pivots[n-1] >= n {
+		return nil, ErrSingular
+	}
+
+	res := make(Matrix, n)
+	for i := range res {
+		res[i] = augmented[i][n:]
+	}
+	return res, nil
+}
+
+// Solves m x = b for an invertible m.
+func (m Matrix) Solve(b []g.GoldilocksField) ([]g.GoldilocksField, error) {
+	if !m.IsSquare() || len(b) != m.Rows() {
+		return nil, fmt.Errorf("cannot solve a %dx%d system with %d right-hand sides", m.Rows(), m.Cols(), len(b))
+	}
+	n := m.Rows()
+	augmented := New(n, n+1)
+	for i := range m {
+		copy(augmented[i], m[i])
+		augmented[i][n] = b[i]
+	}
+	if pivots, _ := augmented.eliminate(); len(pivots) < n || pivots[n-1] >= n {
+		return nil, ErrSingular
+	}
+
+	res := make([]g.GoldilocksField, n)
+	for i := range res {
+		res[i] = augmented[i][n]
+	}
+	return res, nil
+}
+
+// Characteristic polynomial det(X*I - m), by the Faddeev-LeVerrier algorithm.
+// The divisions by 1, ..., n are fine since n is far below the characteristic.
+func (m Matrix) CharPoly() poly.Poly {
+	if !m.IsSquare() {
+		panic(fmt.Sprintf("cannot take the characteristic polynomial of a %dx%d matrix", m.Rows(), m.Cols()))
+	}
+	n := m.Rows()
+	coeffs := make(poly.Poly, n+1)
+	coeffs[n] = g.OneF()
+
+	// a = m * M_k, with M_1 = I and M_{k+1} = m * M_k + c_{n-k} * I.
+	a := m.Clone()
+	for k := 1; k <= n; k++ {
+		trace := g.ZeroF()
+		for i := 0; i < n; i++ {
+			trace = g.AddF(trace, a[i][i])
+		}
+		coeffs[n-k] = g.NegF(g.DivF(trace, g.GoldilocksField(k)))
+		if k < n {
+			for i := 0; i < n; i++ {
+				a[i][i] = g.AddF(a[i][i], coeffs[n-k])
+			}
+			a = m.Mul(a)
+		}
+	}
+	return coeffs
+}
+
+// Minimal polynomial, the monic polynomial of lowest degree vanishing on m. It
+// is the least common multiple of the minimal polynomials of the unit vectors,
+// each found as the first linear dependency in its Krylov sequence.
+func (m Matrix) MinPoly() poly.Poly {
+	if !m.IsSquare() {
+		panic(fmt.Sprintf("cannot take the minimal polynomial of a %dx%d matrix", m.Rows(), m.Cols()))
+	}
+	n := m.Rows()
+	res := poly.Constant(g.OneF())
+	for j := 0; j < n; j++ {
+		v := make([]g.GoldilocksField, n)
+		v[j] = g.OneF()
+		p := m.vectorMinPoly(v)
+		gcd := res.Gcd(p)
+		quo, _ := p.DivRem(gcd)
+		res = res.Mul(quo)
+	}
+	return res.Monic()
+}
+
+// Monic polynomial p of lowest degree with p(m) v = 0.
+func (m Matrix) vectorMinPoly(v []g.GoldilocksField) poly.Poly {
+	n := len(v)
+	// Columns v, m v, ..., m^d v, and the identity to track the combinations.
+	var krylov [][]g.GoldilocksField
+	for d := 0; d <= n; d++ {
+		krylov = append(krylov, v)
+		system := New(n, d+1)
+		for i := 0; i < n; i++ {
+			for k := 0; k <= d; k++ {
+				system[i][k] = krylov[k][i]
+			}
+		}
+		if pivots, _ := system.eliminate(); len(pivots) <= d {
+			// m^d v = -sum_k c_k m^k v, with c read from the last column of
+			// the reduced system since the first d columns are independent.
+			res := make(poly.Poly, d+1)
+			for k := 0; k < d; k++ {
+				res[k] = g.NegF(system[k][d])
+			}
+			res[d] = g.OneF()
+			return res
+		}
+		v = m.MulVec(v)
+	}
+	panic("unreachable: a Krylov sequence has at most n independent vectors")
+}
+
+// Whether every square submatrix of m is invertible. Minors are built up by
+// size with Laplace expansions along their last row, so each is computed once:
+// a 12x12 matrix has about 2.7 million of them.
+func (m Matrix) IsMDS() bool {
+	rows, cols := m.Rows(), m.Cols()
+	if rows == 0 || cols == 0 || rows > 16 || cols > 16 {
+		panic(fmt.Sprintf("cannot check a %dx%d matrix for MDS", rows, cols))
+	}
+
+	// index[mask] is the rank of mask among the masks of the same popcount.
+	rowSubsets, rowIndex := subsets(rows)
+	colSubsets, colIndex := subsets(cols)
+
+	// minors[r][c] for row and column subsets of size k.
+	minors := make([][]g.GoldilocksField, len(rowSubsets[1]))
+	for i, r := range rowSubsets[1] {
+		minors[i] = make([]g.GoldilocksField, len(colSubsets[1]))
+		for j, c := range colSubsets[1] {
+			minors[i][j] = m[bits.TrailingZeros32(r)][bits.TrailingZeros32(c)]
+			if minors[i][j].IsZero() {
+				return false
+			}
+		}
+	}
+
+	n := rows
+	if cols < n {
+		n = cols
+	}
+	for k := 2; k <= n; k++ {
+		next := make([][]g.GoldilocksField, len(rowSubsets[k]))
+		for i, r := range rowSubsets[k] {
+			last := bits.Len32(r) - 1
+			sub := minors[rowIndex[r&^(1<<last)]]
+			next[i] = make([]g.GoldilocksField, len(colSubsets[k]))
+			for j, c := range colSubsets[k] {
+				det := g.ZeroF()
+				pos := 0
+				for rest := c; rest != 0; rest &= rest - 1 {
+					col := bits.TrailingZeros32(rest)
+					term := g.MulF(m[last][col], sub[colIndex[c&^(1<<col)]])
+					// Sign (-1)^((k-1) + pos) of the cofactor.
+					if (k-1+pos)%2 == 1 {
+						det = g.SubF(det, term)
+					} else {
+						det = g.AddF(det, term)
+					}
+					pos++
+				}
+				if det.IsZero() {
+					return false
+				}
+				next[i][j] = det
+			}
+		}
+		minors = next
+	}
+	return true
+}
+
+// Returns the subsets of {0, ..., n-1} grouped by size, and the index of each
+// subset within its group.
+func subsets(n int) ([][]uint32, []int) {
+	bySize := make([][]uint32, n+1)
+	index := make([]int, 1<<n)
+	for mask := uint32(0); mask < 1<<n; mask++ {
+		k := bits.OnesCount32(mask)
+		index[mask] = len(bySize[k])
+		bySize[k] = append(bySize[k], mask)
+	}
+	return bySize, index
+}
+
+// Sufficient condition from the Poseidon2 paper (section 5.3) for a square
+// linear layer m not to admit invariant subspace trails over any number of
+// rounds: for 1 <= i <= 2n, the minimal polynomial of m^i is irreducible of
+// degree n.
+func (m Matrix) IsInvariantSubspaceFree() bool {
+	if !m.IsSquare() {
+		return false
+	}
+	n := m.Rows()
+	power := m
+	for i := 1; i <= 2*n; i++ {
+		p := power.MinPoly()
+		if p.Degree() != n || !p.IsIrreducible() {
+			return false
+		}
+		power = power.Mul(m)
+	}
+	return true
+}
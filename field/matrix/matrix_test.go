@@ -0,0 +1,249 @@
+package matrix
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	"github.com/ppd0705/poseidon_crypto/field/poly"
+)
+
+func randMatrix(rows, cols int) Matrix {
+	m := New(rows, cols)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = g.GoldilocksField(rand.Uint64N(g.ORDER))
+		}
+	}
+	return m
+}
+
+// Determinant by the Leibniz formula, for small matrices.
+func leibnizDeterminant(m Matrix) g.GoldilocksField {
+	n := m.Rows()
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	res := g.ZeroF()
+	var permute func(k int, sign bool)
+	permute = func(k int, sign bool) {
+		if k == n {
+			term := g.OneF()
+			for i := range perm {
+				term = g.MulF(term, m[i][perm[i]])
+			}
+			if sign {
+				res = g.SubF(res, term)
+			} else {
+				res = g.AddF(res, term)
+			}
+			return
+		}
+		for i := k; i < n; i++ {
+			perm[k], perm[i] = perm[i], perm[k]
+			permute(k+1, sign != (i != k))
+			perm[k], perm[i] = perm[i], perm[k]
+		}
+	}
+	permute(0, false)
+	return res
+}
+
+func TestMulTranspose(t *testing.T) {
+	a, b := randMatrix(3, 5), randMatrix(5, 4)
+	ab := a.Mul(b)
+	if ab.Rows() != 3 || ab.Cols() != 4 {
+		t.Fatalf("product is %dx%d, want 3x4", ab.Rows(), ab.Cols())
+	}
+	// (ab)^T = b^T a^T
+	if !ab.Transpose().Equals(b.Transpose().Mul(a.Transpose())) {
+		t.Fatal("(ab)^T != b^T a^T")
+	}
+	if !a.Mul(Identity(5)).Equals(a) || !Identity(3).Mul(a).Equals(a) {
+		t.Fatal("identity is not neutral")
+	}
+
+	v := randMatrix(4, 1)
+	column := make([]g.GoldilocksField, 4)
+	for i := range column {
+		column[i] = v[i][0]
+	}
+	got := ab.MulVec(column)
+	want := ab.Mul(v)
+	for i := range got {
+		if got[i] != want[i][0] {
+			t.Fatalf("MulVec differs from Mul at %d", i)
+		}
+	}
+
+	c := randMatrix(4, 4)
+	if !c.Pow(0).Equals(Identity(4)) {
+		t.Fatal("m^0 is not the identity")
+	}
+	if !c.Pow(5).Equals(c.Mul(c).Mul(c).Mul(c).Mul(c)) {
+		t.Fatal("Pow differs from repeated products")
+	}
+}
+
+func TestInverse(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		m := randMatrix(n, n)
+		inv, err := m.Inverse()
+		if err != nil {
+			t.Fatalf("random %dx%d matrix: %v", n, n, err)
+		}
+		if !m.Mul(inv).Equals(Identity(n)) || !inv.Mul(m).Equals(Identity(n)) {
+			t.Fatalf("%dx%d inverse is wrong", n, n)
+		}
+
+		b := randMatrix(n, 1).Transpose()[0]
+		x, err := m.Solve(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := m.MulVec(x)
+		for i := range got {
+			if got[i] != b[i] {
+				t.Fatalf("%dx%d solution is wrong", n, n)
+			}
+		}
+	}
+
+	// The third row is the sum of the first two.
+	singular := FromUint64([][]uint64{{1, 2, 3}, {4, 5, 6}, {5, 7, 9}})
+	if _, err := singular.Inverse(); err != ErrSingular {
+		t.Fatalf("got %v, want ErrSingular", err)
+	}
+	if _, err := singular.Solve([]g.GoldilocksField{1, 2, 3}); err != ErrSingular {
+		t.Fatalf("got %v, want ErrSingular", err)
+	}
+	if singular.IsInvertible() {
+		t.Fatal("singular matrix reported invertible")
+	}
+}
+
+func TestRank(t *testing.T) {
+	// Products of n x k and k x n random matrices have rank k.
+	for k := 0; k <= 6; k++ {
+		m := randMatrix(6, k).Mul(randMatrix(k, 7))
+		if k == 0 {
+			m = New(6, 7)
+		}
+		if got := m.Rank(); got != k {
+			t.Fatalf("got rank %d, want %d", got, k)
+		}
+		if got := m.Transpose().Rank(); got != k {
+			t.Fatalf("got transposed rank %d, want %d", got, k)
+		}
+	}
+
+	echelon, pivots := FromUint64([][]uint64{{0, 2, 4}, {0, 1, 2}, {1, 1, 1}}).RowEchelon()
+	want := FromUint64([][]uint64{{1, 0, g.ORDER - 1}, {0, 1, 2}, {0, 0, 0}})
+	if !echelon.Equals(want) || len(pivots) != 2 || pivots[0] != 0 || pivots[1] != 1 {
+		t.Fatalf("got %v with pivots %v", echelon, pivots)
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	for n := 1; n <= 6; n++ {
+		m := randMatrix(n, n)
+		if got, want := m.Determinant(), leibnizDeterminant(m); got != want {
+			t.Fatalf("%dx%d: got %d, want %d", n, n, got, want)
+		}
+		// det(ab) = det(a) det(b)
+		b := randMatrix(n, n)
+		if m.Mul(b).Determinant() != g.MulF(m.Determinant(), b.Determinant()) {
+			t.Fatalf("%dx%d: determinant is not multiplicative", n, n)
+		}
+	}
+	if !FromUint64([][]uint64{{1, 2}, {2, 4}}).Determinant().IsZero() {
+		t.Fatal("singular matrix has a non-zero determinant")
+	}
+}
+
+func evalMatrixPoly(p poly.Poly, m Matrix) Matrix {
+	res := New(m.Rows(), m.Cols())
+	for i := len(p) - 1; i >= 0; i-- {
+		res = res.Mul(m).Add(Identity(m.Rows()).ScalarMul(p[i]))
+	}
+	return res
+}
+
+func TestCharPolyMinPoly(t *testing.T) {
+	m := randMatrix(6, 6)
+	charPoly := m.CharPoly()
+	if charPoly.Degree() != 6 {
+		t.Fatalf("characteristic polynomial has degree %d", charPoly.Degree())
+	}
+	if !g.EqualsF(charPoly[0], m.Determinant()) {
+		t.Fatal("constant term is not det(-m) = det(m)")
+	}
+	// Cayley-Hamilton
+	if !evalMatrixPoly(charPoly, m).Equals(New(6, 6)) {
+		t.Fatal("m is not a root of its characteristic polynomial")
+	}
+	if !m.MinPoly().Equals(charPoly) {
+		t.Fatal("a random matrix has a minimal polynomial other than its characteristic polynomial")
+	}
+
+	// diag(2, 2, 3) has minimal polynomial (X - 2)(X - 3).
+	d := Diagonal([]g.GoldilocksField{2, 2, 3})
+	if want := poly.VanishingPoly([]g.GoldilocksField{2, 3}); !d.MinPoly().Equals(want) {
+		t.Fatalf("got minimal polynomial %v, want %v", d.MinPoly(), want)
+	}
+	if want := poly.VanishingPoly([]g.GoldilocksField{2, 2, 3}); !d.CharPoly().Equals(want) {
+		t.Fatalf("got characteristic polynomial %v, want %v", d.CharPoly(), want)
+	}
+}
+
+func TestIsMDS(t *testing.T) {
+	// Cauchy matrices 1 / (x_i - y_j) are MDS.
+	const n = 6
+	cauchy := New(n, n)
+	for i := range cauchy {
+		for j := range cauchy[i] {
+			cauchy[i][j] = g.InverseF(g.GoldilocksField(i + n + j*j + 1))
+		}
+	}
+	if !cauchy.IsMDS() {
+		t.Fatal("Cauchy matrix is not MDS")
+	}
+	if !cauchy.Transpose().IsMDS() {
+		t.Fatal("transposed Cauchy matrix is not MDS")
+	}
+
+	// Make one 2x2 minor vanish.
+	cauchy[1][1] = g.DivF(g.MulF(cauchy[0][1], cauchy[1][0]), cauchy[0][0])
+	if cauchy.IsMDS() {
+		t.Fatal("matrix with a vanishing minor is MDS")
+	}
+	if Identity(3).IsMDS() {
+		t.Fatal("identity is MDS")
+	}
+
+	// The 4x4 external matrix from the Poseidon2 paper is MDS.
+	m4 := FromUint64([][]uint64{{5, 7, 1, 3}, {4, 6, 1, 1}, {1, 3, 5, 7}, {1, 1, 4, 6}})
+	if !m4.IsMDS() {
+		t.Fatal("Poseidon2 M4 is not MDS")
+	}
+}
+
+func TestIsInvariantSubspaceFree(t *testing.T) {
+	// Permutation matrices have the invariant subspace of constant vectors.
+	if Circulant([]g.GoldilocksField{0, 1, 0, 0}).IsInvariantSubspaceFree() {
+		t.Fatal("cyclic shift is invariant subspace free")
+	}
+	// The companion matrix of an irreducible polynomial is, for enough powers.
+	// X^2 - 7 is irreducible since 7 is not a square.
+	if g.LegendreF(7) != g.NegOneF() {
+		t.Skip("7 is a square")
+	}
+	if !FromUint64([][]uint64{{0, 7}, {1, 0}}).MinPoly().IsIrreducible() {
+		t.Fatal("companion matrix of X^2 - 7 has a reducible minimal polynomial")
+	}
+	// Its square is 7*I, with minimal polynomial X - 7 of degree 1.
+	if FromUint64([][]uint64{{0, 7}, {1, 0}}).IsInvariantSubspaceFree() {
+		t.Fatal("matrix whose square is scalar is invariant subspace free")
+	}
+}
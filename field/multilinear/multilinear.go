@@ -0,0 +1,113 @@
+// Package multilinear implements multilinear polynomials over the Goldilocks
+// field, given by their evaluations over the boolean hypercube, and their
+// evaluation at points of the quintic extension.
+package multilinear
+
+import (
+	"fmt"
+	"math/bits"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	ext "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension_plonky2"
+)
+
+// Multilinear polynomial in n variables, given by its 2^n evaluations over
+// {0,1}^n. The first variable is the most significant bit of the index:
+// evals[i] is the value at (b_0, ..., b_{n-1}) with i = sum_j b_j * 2^(n-1-j).
+type MLE []g.GoldilocksField
+
+// Same as MLE with values in the quintic extension, as obtained by fixing
+// variables to extension challenges.
+type MLEExt []ext.Element
+
+func numVars(n int) int {
+	if n <= 0 || n&(n-1) != 0 {
+		panic(fmt.Sprintf("multilinear polynomial has %d evaluations, not a power of two", n))
+	}
+	return bits.TrailingZeros(uint(n))
+}
+
+func (m MLE) NumVars() int {
+	return numVars(len(m))
+}
+
+func (m MLEExt) NumVars() int {
+	return numVars(len(m))
+}
+
+func (m MLE) ToExt() MLEExt {
+	res := make(MLEExt, len(m))
+	for i := range m {
+		res[i] = ext.FromF(m[i])
+	}
+	return res
+}
+
+// Returns the polynomial in n-1 variables m(r, x_1, ..., x_{n-1}).
+func (m MLE) FixFirstVariable(r ext.Element) MLEExt {
+	half := len(m) / 2
+	if m.NumVars() == 0 {
+		panic("no variable left to fix")
+	}
+	res := make(MLEExt, half)
+	for i := range res {
+		// m[i] + r * (m[i + half] - m[i])
+		res[i] = ext.ScalarMul(r, g.SubF(m[i+half], m[i]))
+		res[i][0] = g.AddF(res[i][0], m[i])
+	}
+	return res
+}
+
+// Returns the polynomial in n-1 variables m(r, x_1, ..., x_{n-1}).
+func (m MLEExt) FixFirstVariable(r ext.Element) MLEExt {
+	half := len(m) / 2
+	if m.NumVars() == 0 {
+		panic("no variable left to fix")
+	}
+	res := make(MLEExt, half)
+	for i := range res {
+		res[i] = ext.Add(m[i], ext.Mul(r, ext.Sub(m[i+half], m[i])))
+	}
+	return res
+}
+
+// Evaluates the polynomial at a point of the extension, one coordinate per
+// variable.
+func (m MLE) Evaluate(point []ext.Element) ext.Element {
+	if len(point) != m.NumVars() {
+		panic(fmt.Sprintf("point has %d coordinates but the polynomial has %d variables", len(point), m.NumVars()))
+	}
+	if len(point) == 0 {
+		return ext.FromF(m[0])
+	}
+	return m.FixFirstVariable(point[0]).Evaluate(point[1:])
+}
+
+// Evaluates the polynomial at a point of the extension, one coordinate per
+// variable.
+func (m MLEExt) Evaluate(point []ext.Element) ext.Element {
+	if len(point) != m.NumVars() {
+		panic(fmt.Sprintf("point has %d coordinates but the polynomial has %d variables", len(point), m.NumVars()))
+	}
+	for _, r := range point {
+		m = m.FixFirstVariable(r)
+	}
+	return m[0]
+}
+
+// Returns the evaluations over {0,1}^n of eq(point, x) = prod_j (point_j x_j +
+// (1 - point_j)(1 - x_j)), so that m(point) = sum_x eq(point, x) m(x).
+func Eq(point []ext.Element) MLEExt {
+	res := MLEExt{ext.FP5_ONE}
+	for _, r := range point {
+		next := make(MLEExt, 2*len(res))
+		for i := range res {
+			// The new variable is the least significant bit so far.
+			hi := ext.Mul(res[i], r)
+			next[2*i] = ext.Sub(res[i], hi)
+			next[2*i+1] = hi
+		}
+		res = next
+	}
+	return res
+}
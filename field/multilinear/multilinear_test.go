@@ -0,0 +1,89 @@
+package multilinear
+
+import (
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	ext "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension_plonky2"
+)
+
+func randomMLE(numVars int) MLE {
+	res := make(MLE, 1<<numVars)
+	for i := range res {
+		res[i] = g.SampleF()
+	}
+	return res
+}
+
+func randomPoint(numVars int) []ext.Element {
+	res := make([]ext.Element, numVars)
+	for i := range res {
+		res[i] = ext.Sample()
+	}
+	return res
+}
+
+func TestEvaluateOnHypercube(t *testing.T) {
+	const numVars = 4
+	m := randomMLE(numVars)
+	for i := range m {
+		point := make([]ext.Element, numVars)
+		for j := range point {
+			if (i>>(numVars-1-j))&1 == 1 {
+				point[j] = ext.FP5_ONE
+			}
+		}
+		if got := m.Evaluate(point); !ext.Equals(got, ext.FromF(m[i])) {
+			t.Fatalf("evaluation at vertex %d: got %v, want %d", i, got, m[i])
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	for numVars := 0; numVars <= 5; numVars++ {
+		m := randomMLE(numVars)
+		point := randomPoint(numVars)
+		want := m.ToExt().Evaluate(point)
+		if got := m.Evaluate(point); !ext.Equals(got, want) {
+			t.Fatalf("%d variables: base and extension evaluations differ", numVars)
+		}
+
+		// m(point) = sum_x eq(point, x) m(x)
+		eq := Eq(point)
+		sum := ext.FP5_ZERO
+		for i := range m {
+			sum = ext.Add(sum, ext.ScalarMul(eq[i], m[i]))
+		}
+		if !ext.Equals(sum, want) {
+			t.Fatalf("%d variables: eq sum differs from the evaluation", numVars)
+		}
+	}
+}
+
+func TestEvaluateIsMultilinear(t *testing.T) {
+	// Along any coordinate, the evaluation is an affine function of it.
+	const numVars = 3
+	m := randomMLE(numVars)
+	point := randomPoint(numVars)
+	for j := 0; j < numVars; j++ {
+		at := func(x ext.Element) ext.Element {
+			p := append([]ext.Element(nil), point...)
+			p[j] = x
+			return m.Evaluate(p)
+		}
+		v0, v1 := at(ext.FP5_ZERO), at(ext.FP5_ONE)
+		want := ext.Add(v0, ext.Mul(point[j], ext.Sub(v1, v0)))
+		if got := at(point[j]); !ext.Equals(got, want) {
+			t.Fatalf("evaluation is not affine in variable %d", j)
+		}
+	}
+}
+
+func TestNumVarsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for 3 evaluations")
+		}
+	}()
+	MLE(make([]g.GoldilocksField, 3)).NumVars()
+}
@@ -0,0 +1,164 @@
+package poly
+
+import (
+	"fmt"
+	"math/bits"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+)
+
+func log2Strict(n int) int {
+	if n <= 0 || n&(n-1) != 0 {
+		panic(fmt.Sprintf("domain size %d is not a power of two", n))
+	}
+	return bits.TrailingZeros(uint(n))
+}
+
+// Returns the points of the coset shift*H, where H is the subgroup of order n.
+func cosetPoints(n int, shift g.GoldilocksField) []g.GoldilocksField {
+	points := g.TwoAdicSubgroupF(log2Strict(n))
+	for i := range points {
+		points[i] = g.MulF(points[i], shift)
+	}
+	return points
+}
+
+// Interpolates the polynomial taking the given values over the subgroup of
+// order len(evals).
+func InterpolateSubgroup(evals []g.GoldilocksField) Poly {
+	return Poly(g.INTT(evals))
+}
+
+// Interpolates the polynomial taking the given values over the coset shift*H of
+// the subgroup of order len(evals).
+func InterpolateCoset(evals []g.GoldilocksField, shift g.GoldilocksField) Poly {
+	return Poly(g.CosetINTT(evals, shift))
+}
+
+// Lagrange interpolation of the unique polynomial of degree < len(points) going
+// through (points[i], values[i]). Panics if two points are equal.
+func Interpolate(points, values []g.GoldilocksField) Poly {
+	if len(points) != len(values) {
+		panic(fmt.Sprintf("got %d points but %d values", len(points), len(values)))
+	}
+
+	// Barycentric weights w_i = 1 / prod_{j != i} (x_i - x_j).
+	denominators := make([]g.GoldilocksField, len(points))
+	for i := range points {
+		d := g.OneF()
+		for j := range points {
+			if i != j {
+				diff := g.SubF(points[i], points[j])
+				if diff.IsZero() {
+					panic(fmt.Sprintf("points %d and %d are equal", i, j))
+				}
+				d = g.MulF(d, diff)
+			}
+		}
+		denominators[i] = d
+	}
+	weights := g.BatchInverseF(denominators)
+
+	vanishing := VanishingPoly(points)
+	res := make(Poly, len(points))
+	for i := range points {
+		basis, _ := vanishing.DivByLinear(points[i])
+		c := g.MulF(values[i], weights[i])
+		for k := range basis {
+			res[k] = g.AddF(res[k], g.MulF(basis[k], c))
+		}
+	}
+	return res
+}
+
+// Lagrange interpolation over the quintic extension. Panics if two points are
+// equal.
+func InterpolateExt(points, values []gFp5.Element) PolyExt {
+	if len(points) != len(values) {
+		panic(fmt.Sprintf("got %d points but %d values", len(points), len(values)))
+	}
+
+	denominators := make([]gFp5.Element, len(points))
+	for i := range points {
+		d := gFp5.FP5_ONE
+		for j := range points {
+			if i != j {
+				diff := gFp5.Sub(points[i], points[j])
+				if gFp5.IsZero(diff) {
+					panic(fmt.Sprintf("points %d and %d are equal", i, j))
+				}
+				d = gFp5.Mul(d, diff)
+			}
+		}
+		denominators[i] = d
+	}
+	weights := gFp5.BatchInverse(denominators)
+
+	vanishing := VanishingPolyExt(points)
+	res := make(PolyExt, len(points))
+	for i := range points {
+		basis, _ := vanishing.DivByLinear(points[i])
+		c := gFp5.Mul(values[i], weights[i])
+		for k := range basis {
+			res[k] = gFp5.Add(res[k], gFp5.Mul(basis[k], c))
+		}
+	}
+	return res
+}
+
+// Evaluates at z the polynomial taking the given values over the coset shift*H
+// of the subgroup of order n = len(evals), without interpolating it:
+//
+//	p(z) = (z^n - shift^n) / (n * shift^n) * sum_i evals[i] * x_i / (z - x_i)
+//
+// Use shift = 1 for the subgroup itself.
+func BarycentricEval(evals []g.GoldilocksField, shift, z g.GoldilocksField) g.GoldilocksField {
+	n := len(evals)
+	points := cosetPoints(n, shift)
+
+	diffs := make([]g.GoldilocksField, n)
+	for i, x := range points {
+		diffs[i] = g.SubF(z, x)
+		if diffs[i].IsZero() {
+			return evals[i]
+		}
+	}
+	invs := g.BatchInverseF(diffs)
+
+	sum := g.ZeroF()
+	for i, x := range points {
+		sum = g.AddF(sum, g.MulF(evals[i], g.MulF(x, invs[i])))
+	}
+
+	shiftN := g.ExpF(shift, uint64(n))
+	numerator := g.SubF(g.ExpF(z, uint64(n)), shiftN)
+	denominator := g.MulF(g.GoldilocksField(n), shiftN)
+	return g.MulF(sum, g.DivF(numerator, denominator))
+}
+
+// Same as BarycentricEval, for extension-valued evaluations and an evaluation
+// point in the quintic extension.
+func BarycentricEvalExt(evals []gFp5.Element, shift g.GoldilocksField, z gFp5.Element) gFp5.Element {
+	n := len(evals)
+	points := cosetPoints(n, shift)
+
+	diffs := make([]gFp5.Element, n)
+	for i, x := range points {
+		diffs[i] = gFp5.Sub(z, fToExt(x))
+		if gFp5.IsZero(diffs[i]) {
+			return evals[i]
+		}
+	}
+	invs := gFp5.BatchInverse(diffs)
+
+	sum := gFp5.FP5_ZERO
+	for i, x := range points {
+		sum = gFp5.Add(sum, gFp5.Mul(evals[i], gFp5.Mul(fToExt(x), invs[i])))
+	}
+
+	shiftN := g.ExpF(shift, uint64(n))
+	numerator := gFp5.Sub(gFp5.ExpPowerOf2(z, log2Strict(n)), fToExt(shiftN))
+	denominator := g.InverseF(g.MulF(g.GoldilocksField(n), shiftN))
+	return gFp5.ScalarMul(gFp5.Mul(sum, numerator), g.FromUint64(denominator.ToCanonicalUint64()))
+}
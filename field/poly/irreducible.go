@@ -0,0 +1,85 @@
+package poly
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// Returns p divided by its leading coefficient. The zero polynomial is
+// returned as is.
+func (p Poly) Monic() Poly {
+	p = p.Trim()
+	if len(p) == 0 {
+		return p
+	}
+	return p.ScalarMul(g.InverseF(p[len(p)-1]))
+}
+
+// Monic greatest common divisor, zero if both p and q are zero.
+func (p Poly) Gcd(q Poly) Poly {
+	a, b := p.Trim(), q.Trim()
+	for !b.IsZero() {
+		_, r := a.DivRem(b)
+		a, b = b, r
+	}
+	return a.Monic()
+}
+
+// Returns p^e mod m.
+func (p Poly) PowMod(e uint64, m Poly) Poly {
+	_, base := p.DivRem(m)
+	res := Constant(g.OneF())
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			_, res = res.Mul(base).DivRem(m)
+		}
+		_, base = base.Mul(base).DivRem(m)
+	}
+	_, res = res.DivRem(m)
+	return res
+}
+
+// Rabin's irreducibility test: p of degree n is irreducible if and only if it
+// divides X^(q^n) - X and is coprime with X^(q^(n/r)) - X for every prime r
+// dividing n, where q is the field order. Constants are not irreducible.
+func (p Poly) IsIrreducible() bool {
+	p = p.Monic()
+	n := p.Degree()
+	if n < 1 {
+		return false
+	}
+	if n == 1 {
+		return true
+	}
+
+	// frobenius[k] = X^(q^k) mod p
+	frobenius := make([]Poly, n+1)
+	frobenius[0] = X()
+	for k := 1; k <= n; k++ {
+		frobenius[k] = frobenius[k-1].PowMod(g.ORDER, p)
+	}
+	if !frobenius[n].Equals(X()) {
+		return false
+	}
+	for _, r := range primeFactors(n) {
+		if frobenius[n/r].Sub(X()).Gcd(p).Degree() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func primeFactors(n int) []int {
+	var res []int
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			res = append(res, d)
+			for n%d == 0 {
+				n /= d
+			}
+		}
+	}
+	if n > 1 {
+		res = append(res, n)
+	}
+	return res
+}
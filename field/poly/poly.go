@@ -0,0 +1,228 @@
+package poly
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// Dense univariate polynomial over the Goldilocks field. Coefficients are stored
+// from the constant term upwards.
+type Poly []g.GoldilocksField
+
+// Products of polynomials with at least this many coefficients each go through the NTT.
+const nttMulThreshold = 64
+
+func ZeroPoly() Poly {
+	return Poly{}
+}
+
+// Returns the polynomial c (a constant).
+func Constant(c g.GoldilocksField) Poly {
+	return Poly{c}
+}
+
+// Returns the polynomial X.
+func X() Poly {
+	return Poly{g.ZeroF(), g.OneF()}
+}
+
+// Degree of the polynomial, -1 for the zero polynomial.
+func (p Poly) Degree() int {
+	for i := len(p) - 1; i >= 0; i-- {
+		if !p[i].IsZero() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p Poly) IsZero() bool {
+	return p.Degree() == -1
+}
+
+// Returns a copy of the polynomial without leading zero coefficients.
+func (p Poly) Trim() Poly {
+	res := make(Poly, p.Degree()+1)
+	copy(res, p)
+	return res
+}
+
+func (p Poly) Equals(q Poly) bool {
+	d := p.Degree()
+	if d != q.Degree() {
+		return false
+	}
+	for i := 0; i <= d; i++ {
+		if !g.EqualsF(p[i], q[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Horner evaluation.
+func (p Poly) Eval(x g.GoldilocksField) g.GoldilocksField {
+	res := g.ZeroF()
+	for i := len(p) - 1; i >= 0; i-- {
+		res = g.AddF(g.MulF(res, x), p[i])
+	}
+	return res
+}
+
+// Evaluates the polynomial at every point using Horner's rule.
+func (p Poly) EvalBatch(points []g.GoldilocksField) []g.GoldilocksField {
+	res := make([]g.GoldilocksField, len(points))
+	for i := len(p) - 1; i >= 0; i-- {
+		for j := range points {
+			res[j] = g.AddF(g.MulF(res[j], points[j]), p[i])
+		}
+	}
+	return res
+}
+
+func (p Poly) Add(q Poly) Poly {
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+	res := make(Poly, len(p))
+	copy(res, p)
+	for i := range q {
+		res[i] = g.AddF(res[i], q[i])
+	}
+	return res
+}
+
+func (p Poly) Sub(q Poly) Poly {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	res := make(Poly, n)
+	copy(res, p)
+	for i := range q {
+		res[i] = g.SubF(res[i], q[i])
+	}
+	return res
+}
+
+func (p Poly) Neg() Poly {
+	res := make(Poly, len(p))
+	for i := range p {
+		res[i] = g.NegF(p[i])
+	}
+	return res
+}
+
+func (p Poly) ScalarMul(c g.GoldilocksField) Poly {
+	res := make(Poly, len(p))
+	for i := range p {
+		res[i] = g.MulF(p[i], c)
+	}
+	return res
+}
+
+// Polynomial product. Large operands are multiplied through the NTT, small
+// ones with the schoolbook method.
+func (p Poly) Mul(q Poly) Poly {
+	p, q = p.Trim(), q.Trim()
+	if len(p) == 0 || len(q) == 0 {
+		return ZeroPoly()
+	}
+	if len(p) < nttMulThreshold || len(q) < nttMulThreshold {
+		return p.mulNaive(q)
+	}
+
+	n := 1
+	for n < len(p)+len(q)-1 {
+		n <<= 1
+	}
+	pEvals := g.NTT(p.pad(n))
+	qEvals := g.NTT(q.pad(n))
+	g.MulVec(pEvals, pEvals, qEvals)
+	return Poly(g.INTT(pEvals))[:len(p)+len(q)-1]
+}
+
+func (p Poly) mulNaive(q Poly) Poly {
+	// Below the NTT threshold, no coefficient sums enough products to
+	// overflow an accumulator.
+	accs := make([]g.AccF, len(p)+len(q)-1)
+	for i := range p {
+		for j := range q {
+			accs[i+j].MulAdd(p[i], q[j])
+		}
+	}
+	res := make(Poly, len(accs))
+	for i := range accs {
+		res[i] = accs[i].Reduce()
+	}
+	return res
+}
+
+func (p Poly) pad(n int) []g.GoldilocksField {
+	res := make([]g.GoldilocksField, n)
+	copy(res, p)
+	return res
+}
+
+// Euclidean division: returns (quotient, remainder) with p = quotient*q + remainder
+// and deg(remainder) < deg(q). Panics if q is zero.
+func (p Poly) DivRem(q Poly) (Poly, Poly) {
+	q = q.Trim()
+	if len(q) == 0 {
+		panic("division by zero polynomial")
+	}
+
+	rem := p.Trim()
+	if len(rem) < len(q) {
+		return ZeroPoly(), rem
+	}
+
+	quo := make(Poly, len(rem)-len(q)+1)
+	leadInv := g.InverseF(q[len(q)-1])
+	for i := len(quo) - 1; i >= 0; i-- {
+		c := g.MulF(rem[i+len(q)-1], leadInv)
+		quo[i] = c
+		for j := range q {
+			rem[i+j] = g.SubF(rem[i+j], g.MulF(c, q[j]))
+		}
+	}
+
+	return quo, rem[:len(q)-1].Trim()
+}
+
+// Divides by (X - point) using synthetic division. Returns the quotient and
+// the remainder, which equals p(point).
+func (p Poly) DivByLinear(point g.GoldilocksField) (Poly, g.GoldilocksField) {
+	if len(p) == 0 {
+		return ZeroPoly(), g.ZeroF()
+	}
+	quo := make(Poly, len(p)-1)
+	acc := g.ZeroF()
+	for i := len(p) - 1; i >= 1; i-- {
+		acc = g.AddF(g.MulF(acc, point), p[i])
+		quo[i-1] = acc
+	}
+	return quo, g.AddF(g.MulF(acc, point), p[0])
+}
+
+// Returns X^n - shift^n, which vanishes exactly on the coset shift*H of the
+// subgroup H of order n.
+func VanishingPolySubgroup(n int, shift g.GoldilocksField) Poly {
+	res := make(Poly, n+1)
+	res[0] = g.NegF(g.ExpF(shift, uint64(n)))
+	res[n] = g.AddF(res[n], g.OneF())
+	return res
+}
+
+// Returns the product of (X - x) over all the points.
+func VanishingPoly(points []g.GoldilocksField) Poly {
+	res := Poly{g.OneF()}
+	for _, x := range points {
+		next := make(Poly, len(res)+1)
+		for i := range res {
+			next[i+1] = g.AddF(next[i+1], res[i])
+			next[i] = g.SubF(next[i], g.MulF(res[i], x))
+		}
+		res = next
+	}
+	return res
+}
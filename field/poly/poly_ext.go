@@ -0,0 +1,199 @@
+package poly
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+)
+
+// Dense univariate polynomial over the quintic extension. Coefficients are
+// stored from the constant term upwards.
+type PolyExt []gFp5.Element
+
+func fToExt(f g.GoldilocksField) gFp5.Element {
+	return gFp5.FromF(g.FromUint64(f.ToCanonicalUint64()))
+}
+
+// Lifts the polynomial to the quintic extension.
+func (p Poly) ToExt() PolyExt {
+	res := make(PolyExt, len(p))
+	for i := range p {
+		res[i] = fToExt(p[i])
+	}
+	return res
+}
+
+// Evaluates the polynomial at a point of the quintic extension.
+func (p Poly) EvalExt(x gFp5.Element) gFp5.Element {
+	res := gFp5.FP5_ZERO
+	for i := len(p) - 1; i >= 0; i-- {
+		res = gFp5.Mul(res, x)
+		res[0] = g.Add(res[0], g.FromUint64(p[i].ToCanonicalUint64()))
+	}
+	return res
+}
+
+// Degree of the polynomial, -1 for the zero polynomial.
+func (p PolyExt) Degree() int {
+	for i := len(p) - 1; i >= 0; i-- {
+		if !gFp5.IsZero(p[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p PolyExt) IsZero() bool {
+	return p.Degree() == -1
+}
+
+// Returns a copy of the polynomial without leading zero coefficients.
+func (p PolyExt) Trim() PolyExt {
+	res := make(PolyExt, p.Degree()+1)
+	copy(res, p)
+	return res
+}
+
+func (p PolyExt) Equals(q PolyExt) bool {
+	d := p.Degree()
+	if d != q.Degree() {
+		return false
+	}
+	for i := 0; i <= d; i++ {
+		if !gFp5.Equals(p[i], q[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Horner evaluation.
+func (p PolyExt) Eval(x gFp5.Element) gFp5.Element {
+	res := gFp5.FP5_ZERO
+	for i := len(p) - 1; i >= 0; i-- {
+		res = gFp5.Add(gFp5.Mul(res, x), p[i])
+	}
+	return res
+}
+
+// Evaluates the polynomial at every point using Horner's rule.
+func (p PolyExt) EvalBatch(points []gFp5.Element) []gFp5.Element {
+	res := make([]gFp5.Element, len(points))
+	for i := len(p) - 1; i >= 0; i-- {
+		for j := range points {
+			res[j] = gFp5.Add(gFp5.Mul(res[j], points[j]), p[i])
+		}
+	}
+	return res
+}
+
+func (p PolyExt) Add(q PolyExt) PolyExt {
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+	res := make(PolyExt, len(p))
+	copy(res, p)
+	for i := range q {
+		res[i] = gFp5.Add(res[i], q[i])
+	}
+	return res
+}
+
+func (p PolyExt) Sub(q PolyExt) PolyExt {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	res := make(PolyExt, n)
+	copy(res, p)
+	for i := range q {
+		res[i] = gFp5.Sub(res[i], q[i])
+	}
+	return res
+}
+
+func (p PolyExt) Neg() PolyExt {
+	res := make(PolyExt, len(p))
+	for i := range p {
+		res[i] = gFp5.Neg(p[i])
+	}
+	return res
+}
+
+func (p PolyExt) ScalarMul(c gFp5.Element) PolyExt {
+	res := make(PolyExt, len(p))
+	for i := range p {
+		res[i] = gFp5.Mul(p[i], c)
+	}
+	return res
+}
+
+// Schoolbook polynomial product.
+func (p PolyExt) Mul(q PolyExt) PolyExt {
+	p, q = p.Trim(), q.Trim()
+	if len(p) == 0 || len(q) == 0 {
+		return PolyExt{}
+	}
+
+	res := make(PolyExt, len(p)+len(q)-1)
+	for i := range p {
+		for j := range q {
+			res[i+j] = gFp5.Add(res[i+j], gFp5.Mul(p[i], q[j]))
+		}
+	}
+	return res
+}
+
+// Euclidean division: returns (quotient, remainder) with p = quotient*q + remainder
+// and deg(remainder) < deg(q). Panics if q is zero.
+func (p PolyExt) DivRem(q PolyExt) (PolyExt, PolyExt) {
+	q = q.Trim()
+	if len(q) == 0 {
+		panic("division by zero polynomial")
+	}
+
+	rem := p.Trim()
+	if len(rem) < len(q) {
+		return PolyExt{}, rem
+	}
+
+	quo := make(PolyExt, len(rem)-len(q)+1)
+	leadInv := gFp5.InverseOrZero(q[len(q)-1])
+	for i := len(quo) - 1; i >= 0; i-- {
+		c := gFp5.Mul(rem[i+len(q)-1], leadInv)
+		quo[i] = c
+		for j := range q {
+			rem[i+j] = gFp5.Sub(rem[i+j], gFp5.Mul(c, q[j]))
+		}
+	}
+
+	return quo, rem[:len(q)-1].Trim()
+}
+
+// Divides by (X - point) using synthetic division. Returns the quotient and
+// the remainder, which equals p(point).
+func (p PolyExt) DivByLinear(point gFp5.Element) (PolyExt, gFp5.Element) {
+	if len(p) == 0 {
+		return PolyExt{}, gFp5.FP5_ZERO
+	}
+	quo := make(PolyExt, len(p)-1)
+	acc := gFp5.FP5_ZERO
+	for i := len(p) - 1; i >= 1; i-- {
+		acc = gFp5.Add(gFp5.Mul(acc, point), p[i])
+		quo[i-1] = acc
+	}
+	return quo, gFp5.Add(gFp5.Mul(acc, point), p[0])
+}
+
+// Returns the product of (X - x) over all the points.
+func VanishingPolyExt(points []gFp5.Element) PolyExt {
+	res := PolyExt{gFp5.FP5_ONE}
+	for _, x := range points {
+		next := make(PolyExt, len(res)+1)
+		for i := range res {
+			next[i+1] = gFp5.Add(next[i+1], res[i])
+			next[i] = gFp5.Sub(next[i], gFp5.Mul(res[i], x))
+		}
+		res = next
+	}
+	return res
+}
@@ -0,0 +1,255 @@
+package poly
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+)
+
+func randPoly(n int) Poly {
+	res := make(Poly, n)
+	for i := range res {
+		res[i] = g.GoldilocksField(rand.Uint64N(g.ORDER))
+	}
+	return res
+}
+
+func randPolyExt(n int) PolyExt {
+	res := make(PolyExt, n)
+	for i := range res {
+		res[i] = gFp5.Sample()
+	}
+	return res
+}
+
+func TestEvalBatch(t *testing.T) {
+	p := randPoly(17)
+	points := randPoly(9)
+	evals := p.EvalBatch(points)
+	for i, x := range points {
+		if !g.EqualsF(evals[i], p.Eval(x)) {
+			t.Fatalf("Expected evaluation %d to be %d, but got %d", i, p.Eval(x), evals[i])
+		}
+	}
+
+	pExt := randPolyExt(11)
+	pointsExt := randPolyExt(4)
+	evalsExt := pExt.EvalBatch(pointsExt)
+	for i, x := range pointsExt {
+		if !gFp5.Equals(evalsExt[i], pExt.Eval(x)) {
+			t.Fatalf("Expected extension evaluation %d to be %v, but got %v", i, pExt.Eval(x), evalsExt[i])
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	for _, sizes := range [][2]int{{0, 3}, {1, 1}, {5, 9}, {64, 64}, {100, 300}} {
+		p, q := randPoly(sizes[0]), randPoly(sizes[1])
+		prod := p.Mul(q)
+		if len(p) > 0 && len(q) > 0 && prod.Degree() != p.Degree()+q.Degree() {
+			t.Fatalf("Expected degree %d, but got %d", p.Degree()+q.Degree(), prod.Degree())
+		}
+
+		x := g.GoldilocksField(rand.Uint64N(g.ORDER))
+		if expected := g.MulF(p.Eval(x), q.Eval(x)); !g.EqualsF(prod.Eval(x), expected) {
+			t.Fatalf("Expected (p*q)(x) = %d, but got %d", expected, prod.Eval(x))
+		}
+		if len(p) > 0 && len(q) > 0 && !prod.Equals(p.mulNaive(q)) {
+			t.Fatalf("NTT and schoolbook products differ for sizes %v", sizes)
+		}
+	}
+
+	p, q := randPolyExt(6), randPolyExt(4)
+	x := gFp5.Sample()
+	if expected := gFp5.Mul(p.Eval(x), q.Eval(x)); !gFp5.Equals(p.Mul(q).Eval(x), expected) {
+		t.Fatalf("Expected (p*q)(x) = %v, but got %v", expected, p.Mul(q).Eval(x))
+	}
+}
+
+func TestDivRem(t *testing.T) {
+	p, q := randPoly(40), randPoly(13)
+	quo, rem := p.DivRem(q)
+	if rem.Degree() >= q.Degree() {
+		t.Fatalf("Expected remainder degree below %d, but got %d", q.Degree(), rem.Degree())
+	}
+	if !quo.Mul(q).Add(rem).Equals(p) {
+		t.Fatalf("Expected quotient*q + remainder to equal p")
+	}
+
+	quo, rem = q.DivRem(p)
+	if !quo.IsZero() || !rem.Equals(q) {
+		t.Fatalf("Expected division by a larger polynomial to return (0, q)")
+	}
+
+	pExt, qExt := randPolyExt(12), randPolyExt(5)
+	quoExt, remExt := pExt.DivRem(qExt)
+	if remExt.Degree() >= qExt.Degree() {
+		t.Fatalf("Expected remainder degree below %d, but got %d", qExt.Degree(), remExt.Degree())
+	}
+	if !quoExt.Mul(qExt).Add(remExt).Equals(pExt) {
+		t.Fatalf("Expected quotient*q + remainder to equal p over the extension")
+	}
+}
+
+func TestDivByLinear(t *testing.T) {
+	p := randPoly(20)
+	x := g.GoldilocksField(rand.Uint64N(g.ORDER))
+	quo, rem := p.DivByLinear(x)
+	if !g.EqualsF(rem, p.Eval(x)) {
+		t.Fatalf("Expected remainder to be p(x) = %d, but got %d", p.Eval(x), rem)
+	}
+	if !quo.Mul(Poly{g.NegF(x), g.OneF()}).Add(Constant(rem)).Equals(p) {
+		t.Fatalf("Expected quotient*(X - x) + p(x) to equal p")
+	}
+}
+
+func TestVanishingPoly(t *testing.T) {
+	points := randPoly(10)
+	z := VanishingPoly(points)
+	if z.Degree() != len(points) {
+		t.Fatalf("Expected degree %d, but got %d", len(points), z.Degree())
+	}
+	for _, x := range points {
+		if !z.Eval(x).IsZero() {
+			t.Fatalf("Expected vanishing polynomial to vanish at %d", x)
+		}
+	}
+
+	shift := g.MULTIPLICATIVE_GROUP_GENERATOR
+	zH := VanishingPolySubgroup(16, shift)
+	for _, x := range cosetPoints(16, shift) {
+		if !zH.Eval(x).IsZero() {
+			t.Fatalf("Expected subgroup vanishing polynomial to vanish at %d", x)
+		}
+	}
+	if !zH.Equals(VanishingPoly(cosetPoints(16, shift))) {
+		t.Fatalf("Expected X^n - shift^n to equal the product over the coset")
+	}
+
+	pointsExt := randPolyExt(4)
+	zExt := VanishingPolyExt(pointsExt)
+	for _, x := range pointsExt {
+		if !gFp5.IsZero(zExt.Eval(x)) {
+			t.Fatalf("Expected vanishing polynomial to vanish at %v", x)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	p := randPoly(12)
+	points := randPoly(12)
+	if !Interpolate(points, p.EvalBatch(points)).Equals(p) {
+		t.Fatalf("Expected interpolation to recover the polynomial")
+	}
+
+	pExt := randPolyExt(6)
+	pointsExt := randPolyExt(6)
+	if !InterpolateExt(pointsExt, pExt.EvalBatch(pointsExt)).Equals(pExt) {
+		t.Fatalf("Expected extension interpolation to recover the polynomial")
+	}
+
+	shift := g.MULTIPLICATIVE_GROUP_GENERATOR
+	if !InterpolateSubgroup(p.pad(16)).Equals(Interpolate(cosetPoints(16, g.OneF()), p.pad(16))) {
+		t.Fatalf("Expected subgroup interpolation to match Lagrange interpolation")
+	}
+	if !InterpolateCoset(p.EvalBatch(cosetPoints(16, shift)), shift).Equals(p) {
+		t.Fatalf("Expected coset interpolation to recover the polynomial")
+	}
+}
+
+func TestInterpolateDuplicatePoints(t *testing.T) {
+	expectPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("%s: expected duplicate points to panic", name)
+			}
+		}()
+		f()
+	}
+
+	points := randPoly(4)
+	points[3] = points[1]
+	expectPanic("Interpolate", func() { Interpolate(points, randPoly(4)) })
+
+	pointsExt := randPolyExt(4)
+	pointsExt[2] = pointsExt[0]
+	expectPanic("InterpolateExt", func() { InterpolateExt(pointsExt, randPolyExt(4)) })
+}
+
+func TestBarycentricEval(t *testing.T) {
+	p := randPoly(32)
+	for _, shift := range []g.GoldilocksField{g.OneF(), g.MULTIPLICATIVE_GROUP_GENERATOR} {
+		evals := p.EvalBatch(cosetPoints(32, shift))
+
+		z := g.GoldilocksField(rand.Uint64N(g.ORDER))
+		if res := BarycentricEval(evals, shift, z); !g.EqualsF(res, p.Eval(z)) {
+			t.Fatalf("Expected p(z) = %d, but got %d", p.Eval(z), res)
+		}
+
+		inDomain := g.MulF(shift, g.PrimitiveRootOfUnityF(5))
+		if res := BarycentricEval(evals, shift, inDomain); !g.EqualsF(res, evals[1]) {
+			t.Fatalf("Expected evaluation inside the domain to be %d, but got %d", evals[1], res)
+		}
+
+		evalsExt := make([]gFp5.Element, len(evals))
+		for i, e := range evals {
+			evalsExt[i] = fToExt(e)
+		}
+		zExt := gFp5.Sample()
+		if res := BarycentricEvalExt(evalsExt, shift, zExt); !gFp5.Equals(res, p.EvalExt(zExt)) {
+			t.Fatalf("Expected p(z) = %v, but got %v", p.EvalExt(zExt), res)
+		}
+	}
+}
+
+func TestIsIrreducible(t *testing.T) {
+	x := X()
+	// X^2 - c is irreducible if and only if c is not a square.
+	for _, c := range []g.GoldilocksField{2, 3, 5, 7, 11} {
+		p := x.Mul(x).Sub(Constant(c))
+		if got, want := p.IsIrreducible(), g.LegendreF(c) == g.NegOneF(); got != want {
+			t.Fatalf("X^2 - %d: got irreducible = %v, want %v", c, got, want)
+		}
+	}
+
+	if !x.Sub(Constant(5)).IsIrreducible() {
+		t.Fatal("linear polynomial is reducible")
+	}
+	if Constant(3).IsIrreducible() {
+		t.Fatal("constant is irreducible")
+	}
+	// The quintic extension is defined by X^5 - 3.
+	quintic := VanishingPolySubgroup(5, g.OneF()).Sub(Constant(2))
+	if !quintic.IsIrreducible() {
+		t.Fatal("X^5 - 3 is reducible")
+	}
+	// Products of polynomials are not, even without roots.
+	q := quintic.Mul(quintic.Add(Constant(1)))
+	if q.IsIrreducible() {
+		t.Fatal("product of quintics is irreducible")
+	}
+	if p := randPoly(9); p.Mul(randPoly(4)).IsIrreducible() {
+		t.Fatal("product of random polynomials is irreducible")
+	}
+}
+
+func TestGcd(t *testing.T) {
+	a, b, c := randPoly(5), randPoly(4), randPoly(3)
+	gcd := a.Mul(c).Gcd(b.Mul(c))
+	if !gcd.Equals(c.Monic()) {
+		t.Fatalf("got %v, want %v", gcd, c.Monic())
+	}
+
+	m := randPoly(6)
+	want := Constant(g.OneF())
+	for i := 0; i < 37; i++ {
+		want = want.Mul(a)
+		_, want = want.DivRem(m)
+	}
+	if got := a.PowMod(37, m); !got.Equals(want) {
+		t.Fatal("PowMod differs from repeated products")
+	}
+}
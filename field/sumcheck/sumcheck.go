@@ -0,0 +1,209 @@
+// Package sumcheck implements the sumcheck protocol for products of
+// multilinear polynomials over the Goldilocks field, made non-interactive with
+// a Poseidon2 transcript and challenges in the quintic extension.
+package sumcheck
+
+import (
+	"fmt"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	ext "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension_plonky2"
+	"github.com/ppd0705/poseidon_crypto/field/multilinear"
+)
+
+// Proof that the sum over {0,1}^n of the product of d multilinear polynomials
+// f_1, ..., f_d is the claimed value.
+type Proof struct {
+	// For each round i, the evaluations at 0, 1, ..., d of the univariate
+	// g_i(X) = sum_x f_1(r_0, ..., r_{i-1}, X, x) * ... * f_d(r_0, ..., r_{i-1}, X, x).
+	RoundEvals [][]ext.Element
+	// f_1(r), ..., f_d(r) at the random point r. The verifier only checks
+	// their product: the caller must check each of them against f_j.
+	FinalEvals []ext.Element
+}
+
+// Sum over {0,1}^n of the product of the factors, which must all have the same
+// number of variables.
+func Sum(factors []multilinear.MLE) ext.Element {
+	checkFactors(factors)
+	sum := g.ZeroF()
+	for i := range factors[0] {
+		prod := g.OneF()
+		for _, f := range factors {
+			prod = g.MulF(prod, f[i])
+		}
+		sum = g.AddF(sum, prod)
+	}
+	return ext.FromF(sum)
+}
+
+func checkFactors(factors []multilinear.MLE) {
+	if len(factors) == 0 {
+		panic("sumcheck needs at least one factor")
+	}
+	for _, f := range factors {
+		if len(f) != len(factors[0]) {
+			panic(fmt.Sprintf("factors have %d and %d evaluations", len(factors[0]), len(f)))
+		}
+	}
+}
+
+// Proves the value of Sum(factors). Returns the proof, the claimed sum and the
+// random point r the factors are reduced to.
+func Prove(factors []multilinear.MLE, t *Transcript) (Proof, ext.Element, []ext.Element) {
+	claim := Sum(factors)
+	numVars := factors[0].NumVars()
+	degree := len(factors)
+	t.ObserveExt(claim)
+
+	proof := Proof{RoundEvals: make([][]ext.Element, 0, numVars)}
+	point := make([]ext.Element, 0, numVars)
+	if numVars == 0 {
+		proof.FinalEvals = make([]ext.Element, degree)
+		for j, f := range factors {
+			proof.FinalEvals[j] = ext.FromF(f[0])
+		}
+		t.ObserveExt(proof.FinalEvals...)
+		return proof, claim, point
+	}
+
+	// The first round runs in the base field.
+	evals := roundEvalsBase(factors)
+	proof.RoundEvals = append(proof.RoundEvals, evals)
+	t.ObserveExt(evals...)
+	r := t.ChallengeExt()
+	point = append(point, r)
+	folded := make([]multilinear.MLEExt, degree)
+	for j, f := range factors {
+		folded[j] = f.FixFirstVariable(r)
+	}
+
+	for round := 1; round < numVars; round++ {
+		evals := roundEvalsExt(folded)
+		proof.RoundEvals = append(proof.RoundEvals, evals)
+		t.ObserveExt(evals...)
+		r := t.ChallengeExt()
+		point = append(point, r)
+		for j := range folded {
+			folded[j] = folded[j].FixFirstVariable(r)
+		}
+	}
+
+	proof.FinalEvals = make([]ext.Element, degree)
+	for j, f := range folded {
+		proof.FinalEvals[j] = f[0]
+	}
+	t.ObserveExt(proof.FinalEvals...)
+	return proof, claim, point
+}
+
+// Evaluations at 0, ..., d of the round polynomial, with each factor linear in
+// X: f(X, x) = f(0, x) + X * (f(1, x) - f(0, x)).
+func roundEvalsBase(factors []multilinear.MLE) []ext.Element {
+	degree := len(factors)
+	half := len(factors[0]) / 2
+	sums := make([]g.GoldilocksField, degree+1)
+	prods := make([]g.GoldilocksField, degree+1)
+	for i := 0; i < half; i++ {
+		for k := range prods {
+			prods[k] = g.OneF()
+		}
+		for _, f := range factors {
+			v, step := f[i], g.SubF(f[i+half], f[i])
+			for k := range prods {
+				prods[k] = g.MulF(prods[k], v)
+				v = g.AddF(v, step)
+			}
+		}
+		for k := range sums {
+			sums[k] = g.AddF(sums[k], prods[k])
+		}
+	}
+
+	res := make([]ext.Element, degree+1)
+	for k := range res {
+		res[k] = ext.FromF(sums[k])
+	}
+	return res
+}
+
+func roundEvalsExt(factors []multilinear.MLEExt) []ext.Element {
+	degree := len(factors)
+	half := len(factors[0]) / 2
+	sums := make([]ext.Element, degree+1)
+	prods := make([]ext.Element, degree+1)
+	for i := 0; i < half; i++ {
+		for k := range prods {
+			prods[k] = ext.FP5_ONE
+		}
+		for _, f := range factors {
+			v, step := f[i], ext.Sub(f[i+half], f[i])
+			for k := range prods {
+				prods[k] = ext.Mul(prods[k], v)
+				v = ext.Add(v, step)
+			}
+		}
+		for k := range sums {
+			sums[k] = ext.Add(sums[k], prods[k])
+		}
+	}
+	return sums
+}
+
+// Verifies a proof that the sum over {0,1}^numVars of a product of
+// len(proof.FinalEvals) multilinear polynomials is claim. Returns the random
+// point r: the caller must still check that proof.FinalEvals[j] = f_j(r).
+func Verify(claim ext.Element, numVars int, proof *Proof, t *Transcript) ([]ext.Element, error) {
+	degree := len(proof.FinalEvals)
+	if degree == 0 {
+		return nil, fmt.Errorf("proof has no final evaluations")
+	}
+	if len(proof.RoundEvals) != numVars {
+		return nil, fmt.Errorf("proof has %d rounds but there are %d variables", len(proof.RoundEvals), numVars)
+	}
+	t.ObserveExt(claim)
+
+	point := make([]ext.Element, 0, numVars)
+	for round, evals := range proof.RoundEvals {
+		if len(evals) != degree+1 {
+			return nil, fmt.Errorf("round %d has %d evaluations but the degree is %d", round, len(evals), degree)
+		}
+		if !ext.Equals(ext.Add(evals[0], evals[1]), claim) {
+			return nil, fmt.Errorf("round %d: g(0) + g(1) does not match the claim", round)
+		}
+		t.ObserveExt(evals...)
+		r := t.ChallengeExt()
+		point = append(point, r)
+		claim = interpolateAt(evals, r)
+	}
+
+	prod := ext.FP5_ONE
+	for _, e := range proof.FinalEvals {
+		prod = ext.Mul(prod, e)
+	}
+	if !ext.Equals(prod, claim) {
+		return nil, fmt.Errorf("final evaluations do not match the claim")
+	}
+	t.ObserveExt(proof.FinalEvals...)
+	return point, nil
+}
+
+// Evaluates at r the polynomial of degree < len(evals) taking value evals[k]
+// at k, by Lagrange interpolation.
+func interpolateAt(evals []ext.Element, r ext.Element) ext.Element {
+	res := ext.FP5_ZERO
+	for i := range evals {
+		// prod_{j != i} (r - j) / (i - j)
+		num := ext.FP5_ONE
+		den := g.OneF()
+		for j := range evals {
+			if j == i {
+				continue
+			}
+			num = ext.Mul(num, ext.Sub(r, ext.FromUint64(uint64(j))))
+			den = g.MulF(den, g.SubF(g.GoldilocksField(i), g.GoldilocksField(j)))
+		}
+		res = ext.Add(res, ext.Mul(evals[i], ext.ScalarMul(num, g.InverseF(den))))
+	}
+	return res
+}
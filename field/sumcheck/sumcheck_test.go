@@ -0,0 +1,115 @@
+package sumcheck
+
+import (
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	ext "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension_plonky2"
+	"github.com/ppd0705/poseidon_crypto/field/multilinear"
+)
+
+func randomFactors(numFactors, numVars int) []multilinear.MLE {
+	res := make([]multilinear.MLE, numFactors)
+	for j := range res {
+		res[j] = make(multilinear.MLE, 1<<numVars)
+		for i := range res[j] {
+			res[j][i] = g.SampleF()
+		}
+	}
+	return res
+}
+
+func TestSumcheck(t *testing.T) {
+	for numFactors := 1; numFactors <= 3; numFactors++ {
+		for numVars := 0; numVars <= 6; numVars++ {
+			factors := randomFactors(numFactors, numVars)
+			proof, claim, proverPoint := Prove(factors, NewTranscript())
+
+			point, err := Verify(claim, numVars, &proof, NewTranscript())
+			if err != nil {
+				t.Fatalf("%d factors, %d variables: %v", numFactors, numVars, err)
+			}
+			if len(point) != numVars {
+				t.Fatalf("got a point with %d coordinates, want %d", len(point), numVars)
+			}
+			for i := range point {
+				if !ext.Equals(point[i], proverPoint[i]) {
+					t.Fatalf("prover and verifier points differ at %d", i)
+				}
+			}
+			for j, f := range factors {
+				if !ext.Equals(f.Evaluate(point), proof.FinalEvals[j]) {
+					t.Fatalf("final evaluation %d does not match the factor", j)
+				}
+			}
+		}
+	}
+}
+
+func TestSumcheckRejects(t *testing.T) {
+	const numVars = 4
+	factors := randomFactors(2, numVars)
+	proof, claim, _ := Prove(factors, NewTranscript())
+
+	wrongClaim := ext.Add(claim, ext.FP5_ONE)
+	if _, err := Verify(wrongClaim, numVars, &proof, NewTranscript()); err == nil {
+		t.Fatal("accepted a wrong claim")
+	}
+	if _, err := Verify(claim, numVars+1, &proof, NewTranscript()); err == nil {
+		t.Fatal("accepted a wrong number of variables")
+	}
+
+	for round := range proof.RoundEvals {
+		for k := range proof.RoundEvals[round] {
+			tampered := Proof{RoundEvals: make([][]ext.Element, len(proof.RoundEvals)), FinalEvals: proof.FinalEvals}
+			for i := range proof.RoundEvals {
+				tampered.RoundEvals[i] = append([]ext.Element(nil), proof.RoundEvals[i]...)
+			}
+			tampered.RoundEvals[round][k] = ext.Add(tampered.RoundEvals[round][k], ext.FP5_ONE)
+			if _, err := Verify(claim, numVars, &tampered, NewTranscript()); err == nil {
+				t.Fatalf("accepted a proof with evaluation %d of round %d tampered", k, round)
+			}
+		}
+	}
+
+	tampered := proof
+	tampered.FinalEvals = append([]ext.Element(nil), proof.FinalEvals...)
+	tampered.FinalEvals[0] = ext.Add(tampered.FinalEvals[0], ext.FP5_ONE)
+	if _, err := Verify(claim, numVars, &tampered, NewTranscript()); err == nil {
+		t.Fatal("accepted a proof with a tampered final evaluation")
+	}
+}
+
+func TestInterpolateAt(t *testing.T) {
+	// g(X) = 3X^2 + 2X + 1
+	evals := []ext.Element{ext.FromUint64(1), ext.FromUint64(6), ext.FromUint64(17)}
+	r := ext.Sample()
+	want := ext.Add(ext.Add(ext.ScalarMul(ext.Square(r), 3), ext.ScalarMul(r, 2)), ext.FP5_ONE)
+	if got := interpolateAt(evals, r); !ext.Equals(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	a, b := NewTranscript(), NewTranscript()
+	for i := 0; i < 20; i++ {
+		a.Observe(g.GoldilocksField(i))
+		b.Observe(g.GoldilocksField(i))
+	}
+	for i := 0; i < 20; i++ {
+		if a.Challenge() != b.Challenge() {
+			t.Fatalf("challenge %d differs for the same transcript", i)
+		}
+	}
+
+	c := NewTranscript()
+	c.Observe(1)
+	d := NewTranscript()
+	d.Observe(2)
+	if c.Challenge() == d.Challenge() {
+		t.Fatal("different observations gave the same challenge")
+	}
+	if c.Challenge() == c.Challenge() {
+		t.Fatal("consecutive challenges are equal")
+	}
+}
@@ -0,0 +1,65 @@
+package sumcheck
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	ext "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension_plonky2"
+	poseidon2 "github.com/ppd0705/poseidon_crypto/hash/poseidon2_goldilocks_plonky2"
+)
+
+// Fiat-Shamir transcript over the Poseidon2 permutation, following plonky2's
+// Challenger: observed elements overwrite the rate part of the state, which is
+// permuted whenever RATE elements are pending or a challenge is requested
+// after new observations. Challenges are popped from the end of the rate.
+type Transcript struct {
+	state  [poseidon2.WIDTH]g.GoldilocksField
+	input  []g.GoldilocksField
+	output []g.GoldilocksField
+}
+
+func NewTranscript() *Transcript {
+	return &Transcript{
+		input:  make([]g.GoldilocksField, 0, poseidon2.RATE),
+		output: make([]g.GoldilocksField, 0, poseidon2.RATE),
+	}
+}
+
+func (t *Transcript) Observe(elems ...g.GoldilocksField) {
+	// Any output left is stale once new elements are observed.
+	t.output = t.output[:0]
+	for _, e := range elems {
+		t.input = append(t.input, e)
+		if len(t.input) == poseidon2.RATE {
+			t.duplex()
+		}
+	}
+}
+
+func (t *Transcript) ObserveExt(elems ...ext.Element) {
+	for _, e := range elems {
+		t.Observe(e[:]...)
+	}
+}
+
+func (t *Transcript) Challenge() g.GoldilocksField {
+	if len(t.input) > 0 || len(t.output) == 0 {
+		t.duplex()
+	}
+	res := t.output[len(t.output)-1]
+	t.output = t.output[:len(t.output)-1]
+	return res
+}
+
+func (t *Transcript) ChallengeExt() ext.Element {
+	var res ext.Element
+	for i := range res {
+		res[i] = t.Challenge()
+	}
+	return res
+}
+
+func (t *Transcript) duplex() {
+	copy(t.state[:], t.input)
+	t.input = t.input[:0]
+	poseidon2.Permute(&t.state)
+	t.output = append(t.output[:0], t.state[:poseidon2.RATE]...)
+}
@@ -0,0 +1,48 @@
+package poseidon2
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	"github.com/ppd0705/poseidon_crypto/internal/marshal"
+)
+
+// The binary form of a HashOut is ToLittleEndianBytes, the text form is the hex
+// of the binary one and the JSON form is the text as a string. Decoding rejects
+// non-canonical elements.
+
+func (h HashOut) MarshalBinary() ([]byte, error) {
+	return h.ToLittleEndianBytes(), nil
+}
+
+func (h *HashOut) UnmarshalBinary(data []byte) error {
+	res, err := HashOutFromLittleEndianBytes(data)
+	if err != nil {
+		return err
+	}
+	*h = res
+	return nil
+}
+
+func (h HashOut) MarshalText() ([]byte, error) {
+	return marshal.EncodeHex(h.ToLittleEndianBytes()), nil
+}
+
+func (h *HashOut) UnmarshalText(text []byte) error {
+	data, err := marshal.DecodeHex(text, 4*g.Bytes)
+	if err != nil {
+		return err
+	}
+	return h.UnmarshalBinary(data)
+}
+
+func (h HashOut) MarshalJSON() ([]byte, error) {
+	text, _ := h.MarshalText()
+	return marshal.MarshalJSONText(text)
+}
+
+func (h *HashOut) UnmarshalJSON(data []byte) error {
+	text, err := marshal.UnmarshalJSONText(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return h.UnmarshalText(text)
+}
@@ -0,0 +1,86 @@
+package poseidon2_generic
+
+import (
+	"encoding/binary"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// hash.Hash over arbitrary byte strings. The bytes are split into chunks of
+// g.PackedBytesPerElement little-endian bytes, as by g.PackBytesF, so every
+// chunk is a canonical element and Write never fails. Sum zero-pads the last
+// chunk and absorbs the total number of bytes as a last element: the input is
+// PackBytesF(b) with the length moved from the front to the end, which keeps
+// the encoding injective while streaming.
+type Digest[F any, PF g.Field[F]] struct {
+	sponge   Sponge[F, PF]
+	partial  [g.PackedBytesPerElement]byte // bytes of a chunk not fully written yet
+	nPartial int
+	length   uint64
+}
+
+func (p *Poseidon2[F, PF]) NewDigest() *Digest[F, PF] {
+	return &Digest[F, PF]{sponge: *p.NewSponge()}
+}
+
+// Reset resets the Hash to its initial state.
+func (d *Digest[F, PF]) Reset() {
+	d.sponge.Reset()
+	d.nPartial = 0
+	d.length = 0
+}
+
+// Get chunk by chunk, absorbing each one as soon as all of its bytes are
+// written.
+func (d *Digest[F, PF]) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.length += uint64(n)
+	for len(p) > 0 {
+		k := copy(d.partial[d.nPartial:], p)
+		d.nPartial += k
+		p = p[k:]
+		if d.nPartial < g.PackedBytesPerElement {
+			break
+		}
+		d.sponge.Absorb(d.chunk())
+		d.nPartial = 0
+	}
+	return n, nil
+}
+
+// The zero-padded element of the partial chunk.
+func (d *Digest[F, PF]) chunk() F {
+	var buf [8]byte
+	copy(buf[:], d.partial[:d.nPartial])
+	var elem F
+	PF(&elem).SetUint64(binary.LittleEndian.Uint64(buf[:]))
+	return elem
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *Digest[F, PF]) Size() int {
+	return g.Bytes * OUT
+}
+
+// BlockSize returns the number of bytes absorbed per permutation.
+func (d *Digest[F, PF]) BlockSize() int {
+	return g.PackedBytesPerElement * d.sponge.p.perm.rate
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *Digest[F, PF]) Sum(b []byte) []byte {
+	sponge := d.sponge
+	if d.nPartial > 0 {
+		sponge.Absorb(d.chunk())
+	}
+	var length F
+	PF(&length).SetUint64(d.length)
+	sponge.Absorb(length)
+
+	var h [OUT]F
+	for _, elem := range sponge.SqueezeInto(h[:]) {
+		b = binary.LittleEndian.AppendUint64(b, PF(&elem).Uint64())
+	}
+	return b
+}
@@ -0,0 +1,88 @@
+package poseidon2_generic
+
+import "fmt"
+
+// The widest state of a Poseidon2 instance.
+const MAX_WIDTH = 20
+
+// Parameters of a Poseidon2 instance over Goldilocks, for widths that are
+// multiples of 4 from 8 to MAX_WIDTH, see NewWithParams. PARAMS_12_PLONKY3 is
+// the instance of New.
+type Poseidon2Params struct {
+	Width   int
+	Rate    int // Width minus a capacity of OUT elements
+	RoundsF int
+	RoundsP int
+	// RoundsF rows of Width constants, the first RoundsF/2 for the rounds
+	// before the partial rounds.
+	External [][]uint64
+	// RoundsP constants, added to the first element only.
+	Internal []uint64
+	// The internal matrix is the all-ones matrix plus diag(Diag).
+	Diag []uint64
+	// The external matrix is circ(2 M4, M4, ..., M4) on blocks of 4. The
+	// entries are below 2^16, as they are small in every instance.
+	M4 [4][4]uint64
+}
+
+var (
+	// M4 = circ(2, 3, 1, 1) of Plonky3.
+	M4_PLONKY3 = [4][4]uint64{{2, 3, 1, 1}, {1, 2, 3, 1}, {1, 1, 2, 3}, {3, 1, 1, 2}}
+	// M4 of the Poseidon2 paper and of the HorizenLabs reference implementation.
+	M4_HORIZEN_LABS = [4][4]uint64{{5, 7, 1, 3}, {4, 6, 1, 1}, {1, 3, 5, 7}, {1, 1, 4, 6}}
+)
+
+// The instance of Poseidon2 and of the poseidon2_goldilocks packages.
+var PARAMS_12_PLONKY3 = &Poseidon2Params{
+	Width:    WIDTH,
+	Rate:     RATE,
+	RoundsF:  ROUNDS_F,
+	RoundsP:  ROUNDS_P,
+	External: sliceRows(EXTERNAL_CONSTANTS[:]),
+	Internal: INTERNAL_CONSTANTS[:],
+	Diag:     MATRIX_DIAG_12_U64[:],
+	M4:       M4_PLONKY3,
+}
+
+func sliceRows(rows [][WIDTH]uint64) [][]uint64 {
+	res := make([][]uint64, len(rows))
+	for i := range rows {
+		res[i] = rows[i][:]
+	}
+	return res
+}
+
+func (params *Poseidon2Params) Validate() error {
+	w := params.Width
+	if w < 8 || w > MAX_WIDTH || w%4 != 0 {
+		return fmt.Errorf("width should be a multiple of 4 between 8 and %d but is %d", MAX_WIDTH, w)
+	}
+	if params.Rate <= 0 || params.Rate >= w {
+		return fmt.Errorf("rate should be between 1 and %d but is %d", w-1, params.Rate)
+	}
+	if params.RoundsF <= 0 || params.RoundsF%2 != 0 {
+		return fmt.Errorf("number of full rounds should be positive and even but is %d", params.RoundsF)
+	}
+	if len(params.External) != params.RoundsF {
+		return fmt.Errorf("expected %d rows of external constants but got %d", params.RoundsF, len(params.External))
+	}
+	for r, row := range params.External {
+		if len(row) != w {
+			return fmt.Errorf("external constants of round %d should have %d elements but have %d", r, w, len(row))
+		}
+	}
+	if len(params.Internal) != params.RoundsP {
+		return fmt.Errorf("expected %d internal constants but got %d", params.RoundsP, len(params.Internal))
+	}
+	if len(params.Diag) != w {
+		return fmt.Errorf("internal diagonal should have %d elements but has %d", w, len(params.Diag))
+	}
+	for _, row := range params.M4 {
+		for _, m := range row {
+			if m >= 1<<16 {
+				return fmt.Errorf("entries of M4 should be below 2^16 but %d is not", m)
+			}
+		}
+	}
+	return nil
+}
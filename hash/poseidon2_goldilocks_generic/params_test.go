@@ -0,0 +1,322 @@
+package poseidon2_generic
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	"github.com/ppd0705/poseidon_crypto/field/matrix"
+)
+
+var allParams = []*Poseidon2Params{PARAMS_8, PARAMS_12, PARAMS_16, PARAMS_20, PARAMS_12_PLONKY3}
+
+// Permutation of 0, 1, ..., Width-1, as given by the HorizenLabs reference
+// implementation. The width-8 output is also the expected value of Plonky3's
+// test_poseidon2_width_8_range, which runs the HorizenLabs constants.
+func TestParamsKnownAnswers(t *testing.T) {
+	vectors := map[*Poseidon2Params][]uint64{
+		PARAMS_8: {
+			0xc5fb1cfe0b4697bb, 0x4a4a32ff849af473, 0xd2fd266077f8efba, 0xf4ad9b74e833916d,
+			0xe6648eb0acc11463, 0x8d5529a930d75194, 0xe8c993aa10da6c90, 0xa73104a95b68031c,
+		},
+		PARAMS_12: {
+			0x01eaef96bdf1c0c1, 0x1f0d2cc525b2540c, 0x6282c1dfe1e0358d, 0xe780d721f698e1e6,
+			0x280c0b6f753d833b, 0x1b942dd5023156ab, 0x43f0df3fcccb8398, 0xe8e8190585489025,
+			0x56bdbf72f77ada22, 0x7911c32bf9dcd705, 0xec467926508fbe67, 0x6a50450ddf85a6ed,
+		},
+	}
+
+	for params, expected := range vectors {
+		testParamsPermute[g.GoldilocksField](t, params, expected)
+		testParamsPermute[g.Element](t, params, expected)
+	}
+}
+
+// Permutation of 0, 1, ..., Width-1, computed with this package: no known
+// answers of the HorizenLabs reference are at hand for these widths. Their
+// round constants and diagonals are generated like those of widths 8 and 12,
+// see TestParamsConstants and TestParamsDiagonals, and these outputs guard
+// against regressions.
+func TestParamsPermute(t *testing.T) {
+	vectors := map[*Poseidon2Params][]uint64{
+		PARAMS_16: {
+			0x85c54702470d9756, 0xaa53c7a7d52d9898, 0x285128096efb0dd7, 0xf3fde5edd3050ac8,
+			0xc7b65efd040df908, 0x4be3f6c467f57ae9, 0x274e9a67b41754fb, 0x0f7d39cd5de94dac,
+			0xd0224b9794d0b78c, 0x372f6139570042e1, 0xce6e8a93dc4ec26c, 0xace65e30a4daf7af,
+			0x016f2824cc1ba3db, 0x2e8f3af37c434dec, 0xc80831bb6e09da01, 0x3a7d670bf1a86ee8,
+		},
+		PARAMS_20: {
+			0x73006638ef74156d, 0x9ab9847cae2e034f, 0xd07d5d0bdd7fa381, 0xfee00b15437e6cb2,
+			0xd2ea9f92a39565af, 0x88f76060b66048a4, 0xb3ef72d9ff5d6ce8, 0xd86ef7dc8420f735,
+			0x51011a9f0d30c392, 0x19b7c7af74eee8c1, 0xb2232963cf11cb58, 0xda533dbd466b259c,
+			0x57b78483e90990d0, 0x29e8fee1c8d80311, 0x7e47d4ec6f9a7bb7, 0xc6e1b0d8a716f6bc,
+			0x95fd48cd079d5ed8, 0xb0717f7becb4951a, 0x71fca265a9c41428, 0xab30d820dec7d9af,
+		},
+	}
+
+	for params, expected := range vectors {
+		testParamsPermute[g.GoldilocksField](t, params, expected)
+		testParamsPermute[g.Element](t, params, expected)
+	}
+}
+
+func testParamsPermute[F any, PF g.Field[F]](t *testing.T, params *Poseidon2Params, expected []uint64) {
+	p, err := NewWithParams[F, PF](params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := make([]F, params.Width)
+	for i := range state {
+		PF(&state[i]).SetUint64(uint64(i))
+	}
+	p.PermuteSlice(state)
+	for i := range state {
+		if PF(&state[i]).Uint64() != expected[i] {
+			t.Fatalf("width %d: expected %x at %d, got %x", params.Width, expected[i], i, PF(&state[i]).Uint64())
+		}
+	}
+}
+
+// PARAMS_12_PLONKY3 describes the fixed instance.
+func TestParamsFixedInstance(t *testing.T) {
+	fixed := New[g.GoldilocksField]()
+	p, err := NewWithParams[g.GoldilocksField](PARAMS_12_PLONKY3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewSource(25))
+	for iter := 0; iter < 10; iter++ {
+		state := randomState[g.GoldilocksField](rng)
+		res := append([]g.GoldilocksField(nil), state[:]...)
+		fixed.Permute(&state)
+		p.PermuteSlice(res)
+		if state != [WIDTH]g.GoldilocksField(res) {
+			t.Fatalf("expected %v, got %v", state, res)
+		}
+
+		input := make([]g.GoldilocksField, iter*3)
+		for i := range input {
+			input[i] = g.GoldilocksField(rng.Uint64() % g.ORDER)
+		}
+		expected := fixed.HashNToMNoPad(input, 2*RATE+1)
+		outputs := p.HashNToMNoPad(input, 2*RATE+1)
+		for i := range expected {
+			if outputs[i].ToCanonicalUint64() != expected[i].ToCanonicalUint64() {
+				t.Fatalf("input %v: expected %v, got %v", input, expected, outputs)
+			}
+		}
+	}
+}
+
+func TestParamsLinearLayers(t *testing.T) {
+	for _, params := range allParams {
+		p, err := NewWithParams[g.GoldilocksField](params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := params.Width
+
+		internal := matrix.FromLinearMap(w, func(v []g.GoldilocksField) []g.GoldilocksField {
+			p.perm.internalLinearLayer(v)
+			return v
+		})
+		expected := matrix.New(w, w)
+		for i := range expected {
+			for j := range expected[i] {
+				expected[i][j] = g.OneF()
+			}
+			expected[i][i] = g.AddF(g.OneF(), g.GoldilocksField(params.Diag[i]))
+		}
+		if !internal.Equals(expected) {
+			t.Fatalf("width %d: the internal layer is not J + diag(Diag)", w)
+		}
+		// About a second for each of the wide matrices.
+		if (w <= 12 || !testing.Short()) && !internal.IsInvariantSubspaceFree() {
+			t.Fatalf("width %d: the internal matrix admits invariant subspaces", w)
+		}
+
+		external := matrix.FromLinearMap(w, func(v []g.GoldilocksField) []g.GoldilocksField {
+			p.perm.externalLinearLayer(v)
+			return v
+		})
+		for i := range expected {
+			for j := range expected[i] {
+				coef := params.M4[i%4][j%4]
+				if i/4 == j/4 {
+					coef *= 2
+				}
+				expected[i][j] = g.GoldilocksField(coef)
+			}
+		}
+		if !external.Equals(expected) {
+			t.Fatalf("width %d: the external layer is not circ(2 M4, M4, ..., M4)", w)
+		}
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	for _, params := range allParams {
+		if err := params.Validate(); err != nil {
+			t.Fatalf("width %d: %v", params.Width, err)
+		}
+	}
+
+	invalid := *PARAMS_8
+	invalid.Width = 10
+	if _, err := NewWithParams[g.GoldilocksField](&invalid); err == nil {
+		t.Fatal("expected width 10 to be rejected")
+	}
+	invalid = *PARAMS_8
+	invalid.Internal = invalid.Internal[1:]
+	if _, err := NewWithParams[g.GoldilocksField](&invalid); err == nil {
+		t.Fatal("expected missing internal constants to be rejected")
+	}
+	invalid = *PARAMS_8
+	invalid.M4[0][0] = 1 << 16
+	if _, err := NewWithParams[g.GoldilocksField](&invalid); err == nil {
+		t.Fatal("expected large M4 entries to be rejected")
+	}
+}
+
+// The round constants are those of the Grain LFSR, as generated by the
+// HorizenLabs parameter script: RoundsF/2 rows of Width elements, one element
+// per partial round, then RoundsF/2 rows again.
+func TestParamsConstants(t *testing.T) {
+	for _, params := range []*Poseidon2Params{PARAMS_8, PARAMS_12, PARAMS_16, PARAMS_20} {
+		gr := newGrainLFSR(params.Width, params.RoundsF, params.RoundsP)
+		check := func(what string, c uint64) {
+			if e := gr.element(); e != c {
+				t.Fatalf("width %d, %s: expected %x, got %x", params.Width, what, e, c)
+			}
+		}
+		half := params.RoundsF / 2
+		for r := 0; r < half; r++ {
+			for _, c := range params.External[r] {
+				check("external constants", c)
+			}
+		}
+		for _, c := range params.Internal {
+			check("internal constants", c)
+		}
+		for r := half; r < params.RoundsF; r++ {
+			for _, c := range params.External[r] {
+				check("external constants", c)
+			}
+		}
+
+	}
+}
+
+// The diagonal is read from the Grain LFSR after the round constants, Width
+// elements d_i at a time, until the matrix of ones with d_i on its diagonal is
+// free of invariant subspace trails. That matrix is J + diag(Diag), so Diag
+// holds d_i - 1.
+func TestParamsDiagonals(t *testing.T) {
+	if testing.Short() {
+		t.Skip("a few seconds of invariant subspace checks")
+	}
+	for _, params := range []*Poseidon2Params{PARAMS_8, PARAMS_12, PARAMS_16, PARAMS_20} {
+		w := params.Width
+		gr := newGrainLFSR(w, params.RoundsF, params.RoundsP)
+		for i := 0; i < params.RoundsF*w+params.RoundsP; i++ {
+			gr.element()
+		}
+
+		for group := 0; ; group++ {
+			diag := make([]uint64, w)
+			m := matrix.New(w, w)
+			for i := range m {
+				d := gr.element()
+				diag[i] = g.SubF(g.GoldilocksField(d), g.OneF()).ToCanonicalUint64()
+				for j := range m[i] {
+					m[i][j] = g.OneF()
+				}
+				m[i][i] = g.GoldilocksField(d)
+			}
+			if !m.IsInvariantSubspaceFree() {
+				continue
+			}
+			for i := range diag {
+				if diag[i] != params.Diag[i] {
+					t.Fatalf("width %d, group %d: expected diagonal %x, got %x", w, group, diag, params.Diag)
+				}
+			}
+			break
+		}
+	}
+}
+
+// The Grain LFSR of the Poseidon reference scripts for a prime field of 64 bits
+// and the s-box x^alpha, in its self-shrinking mode.
+type grainLFSR struct {
+	state [80]uint8
+}
+
+func newGrainLFSR(width, roundsF, roundsP int) *grainLFSR {
+	var gr grainLFSR
+	bits := gr.state[:0]
+	push := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, uint8(v>>i&1))
+		}
+	}
+	push(1, 2) // prime field
+	push(0, 4) // x^alpha
+	push(64, 12)
+	push(width, 12)
+	push(roundsF, 10)
+	push(roundsP, 10)
+	for len(bits) < 80 {
+		bits = append(bits, 1)
+	}
+	for i := 0; i < 160; i++ {
+		gr.next()
+	}
+	return &gr
+}
+
+func (gr *grainLFSR) next() uint8 {
+	s := &gr.state
+	b := s[62] ^ s[51] ^ s[38] ^ s[23] ^ s[13] ^ s[0]
+	copy(s[:], s[1:])
+	s[79] = b
+	return b
+}
+
+// Output bits are the second of pairs whose first bit is set.
+func (gr *grainLFSR) bit() uint64 {
+	for {
+		keep := gr.next()
+		b := gr.next()
+		if keep == 1 {
+			return uint64(b)
+		}
+	}
+}
+
+// Big-endian 64-bit samples, rejected until below ORDER.
+func (gr *grainLFSR) element() uint64 {
+	for {
+		var v uint64
+		for i := 0; i < 64; i++ {
+			v = v<<1 | gr.bit()
+		}
+		if v < g.ORDER {
+			return v
+		}
+	}
+}
+
+func BenchmarkParamsPermute(b *testing.B) {
+	for _, params := range []*Poseidon2Params{PARAMS_8, PARAMS_12, PARAMS_20} {
+		p, _ := NewWithParams[g.GoldilocksField](params)
+		state := make([]g.GoldilocksField, params.Width)
+		b.Run(fmt.Sprint(params.Width), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p.PermuteSlice(state)
+			}
+		})
+	}
+}
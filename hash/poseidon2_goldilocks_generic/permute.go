@@ -0,0 +1,121 @@
+package poseidon2_generic
+
+import (
+	"math/bits"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// The permutation of an instance on GoldilocksField, which Poseidon2.Permute
+// runs for every representation. The constants are copied from the parameters,
+// so that they cannot change under a Poseidon2.
+type permutation struct {
+	params   *Poseidon2Params
+	width    int
+	rate     int
+	external [][]g.GoldilocksField
+	internal []g.GoldilocksField
+	diag     []g.GoldilocksField
+	m4       [4][4]uint64
+}
+
+// params must be valid.
+func newPermutation(params *Poseidon2Params) *permutation {
+	p := &permutation{
+		params:   params,
+		width:    params.Width,
+		rate:     params.Rate,
+		external: make([][]g.GoldilocksField, params.RoundsF),
+		internal: make([]g.GoldilocksField, params.RoundsP),
+		diag:     make([]g.GoldilocksField, params.Width),
+		m4:       params.M4,
+	}
+	for r := range p.external {
+		p.external[r] = make([]g.GoldilocksField, params.Width)
+		for i := range p.external[r] {
+			p.external[r][i] = g.GoldilocksField(params.External[r][i])
+		}
+	}
+	for r := range p.internal {
+		p.internal[r] = g.GoldilocksField(params.Internal[r])
+	}
+	for i := range p.diag {
+		p.diag[i] = g.GoldilocksField(params.Diag[i])
+	}
+	return p
+}
+
+// Permutes state, of width elements. The linear layers sum unreduced values and
+// reduce each output once, and the s-box of the full rounds runs on the whole
+// state with g.Pow7Vec.
+func (p *permutation) permute(state []g.GoldilocksField) {
+	half := len(p.external) / 2
+	p.externalLinearLayer(state)
+	for r := 0; r < half; r++ {
+		p.fullRound(state, p.external[r])
+	}
+	for _, c := range p.internal {
+		state[0] = sbox(g.AddF(state[0], c))
+		p.internalLinearLayer(state)
+	}
+	for r := half; r < len(p.external); r++ {
+		p.fullRound(state, p.external[r])
+	}
+}
+
+func (p *permutation) fullRound(state, constants []g.GoldilocksField) {
+	constants = constants[:len(state)]
+	for i := range state {
+		state[i] = g.AddF(state[i], constants[i])
+	}
+	g.Pow7Vec(state, state)
+	p.externalLinearLayer(state)
+}
+
+// circ(2 M4, M4, ..., M4) is M4 (x_b + s) on every block x_b, with s the sums of
+// each position over the blocks. The entries of M4 are small, so the products
+// of the 32-bit halves of the elements sum in 64 bits and an output costs a
+// single reduction, as the MDS layer of poseidon_goldilocks.
+func (p *permutation) externalLinearLayer(state []g.GoldilocksField) {
+	var lo, hi [MAX_WIDTH]uint64
+	var sumLo, sumHi [4]uint64
+	for i := range state {
+		lo[i] = uint64(state[i]) & 0xffffffff
+		hi[i] = uint64(state[i]) >> 32
+		sumLo[i%4] += lo[i]
+		sumHi[i%4] += hi[i]
+	}
+
+	for b := 0; b < len(state); b += 4 {
+		for r := 0; r < 4; r++ {
+			var accLo, accHi uint64
+			for c := 0; c < 4; c++ {
+				accLo += p.m4[r][c] * (lo[b+c] + sumLo[c])
+				accHi += p.m4[r][c] * (hi[b+c] + sumHi[c])
+			}
+			// accLo + accHi * 2^32 as a 128-bit integer.
+			l, carry := bits.Add64(accLo, accHi<<32, 0)
+			state[b+r] = g.Reduce128F(accHi>>32+carry, l)
+		}
+	}
+}
+
+func (p *permutation) internalLinearLayer(state []g.GoldilocksField) {
+	diag := p.diag[:len(state)]
+	var sum g.AccF
+	for i := range state {
+		sum.Add(state[i])
+	}
+	for i := range state {
+		acc := sum
+		acc.MulAdd(state[i], diag[i])
+		state[i] = acc.Reduce()
+	}
+}
+
+// x^D with D = 7.
+func sbox(x g.GoldilocksField) g.GoldilocksField {
+	x2 := g.SquareF(x)
+	x6 := g.SquareF(g.MulF(x2, x))
+	return g.MulF(x6, x)
+}
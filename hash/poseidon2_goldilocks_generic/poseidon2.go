@@ -0,0 +1,183 @@
+// Package poseidon2_generic implements the Goldilocks Poseidon2 permutation and
+// the hash helpers built on it once for every representation of the field, see
+// goldilocks.Field. poseidon2_goldilocks and poseidon2_goldilocks_plonky2 are
+// instances of it.
+package poseidon2_generic
+
+import (
+	"fmt"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// Poseidon2 over the field representation F, for the instance of its
+// parameters. The permutation itself runs on GoldilocksField whatever F is, see
+// Permute, so that its layers are written once for every representation and
+// every width.
+type Poseidon2[F any, PF g.Field[F]] struct {
+	perm *permutation
+}
+
+var plonky3 = newPermutation(PARAMS_12_PLONKY3)
+
+// The instance PARAMS_12_PLONKY3.
+func New[F any, PF g.Field[F]]() *Poseidon2[F, PF] {
+	return &Poseidon2[F, PF]{perm: plonky3}
+}
+
+// The instance of params, for a width chosen at runtime.
+func NewWithParams[F any, PF g.Field[F]](params *Poseidon2Params) (*Poseidon2[F, PF], error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Poseidon2 parameters: %w", err)
+	}
+	return &Poseidon2[F, PF]{perm: newPermutation(params)}, nil
+}
+
+func (p *Poseidon2[F, PF]) Params() *Poseidon2Params {
+	return p.perm.params
+}
+
+// The round constants and the internal diagonal converted to F, for the typed
+// tables of the instances of this package.
+func Constants[F any, PF g.Field[F]]() (external [ROUNDS_F][WIDTH]F, internal [ROUNDS_P]F, diag [WIDTH]F) {
+	for r := 0; r < ROUNDS_F; r++ {
+		for i := 0; i < WIDTH; i++ {
+			PF(&external[r][i]).SetUint64(EXTERNAL_CONSTANTS[r][i])
+		}
+	}
+	for r := 0; r < ROUNDS_P; r++ {
+		PF(&internal[r]).SetUint64(INTERNAL_CONSTANTS[r])
+	}
+	for i := 0; i < WIDTH; i++ {
+		PF(&diag[i]).SetUint64(MATRIX_DIAG_12_U64[i])
+	}
+	return external, internal, diag
+}
+
+func (p *Poseidon2[F, PF]) HashNToOne(input [][OUT]F) [OUT]F {
+	return HashNToOne(p, input)
+}
+
+// Poseidon2.HashNToOne for any hash type H of OUT elements, such as the HashOut
+// of the instance packages.
+func HashNToOne[F any, PF g.Field[F], H ~[OUT]F](p *Poseidon2[F, PF], input []H) H {
+	if len(input) == 1 {
+		return input[0]
+	}
+
+	res := H(p.HashTwoToOne(input[0], input[1]))
+	for i := 2; i < len(input); i++ {
+		res = p.HashTwoToOne(res, input[i])
+	}
+
+	return res
+}
+
+func (p *Poseidon2[F, PF]) HashTwoToOne(input1, input2 [OUT]F) [OUT]F {
+	return p.HashNToHashNoPad([]F{input1[0], input1[1], input1[2], input1[3], input2[0], input2[1], input2[2], input2[3]})
+}
+
+func (p *Poseidon2[F, PF]) HashNToHashNoPad(input []F) [OUT]F {
+	res := p.HashNToMNoPad(input, OUT)
+	return [OUT]F{res[0], res[1], res[2], res[3]}
+}
+
+// Sponge without padding: the input is absorbed Rate elements at a time by
+// overwriting the rate part of the state, then numOutputs elements are squeezed.
+// Inputs that only differ by trailing zeros collide, e.g. [x] and [x, 0]: use
+// HashNToMPad or HashNToMWithLength when the length is not fixed.
+func (p *Poseidon2[F, PF]) HashNToMNoPad(input []F, numOutputs int) []F {
+	var buf [MAX_WIDTH]F
+	perm := buf[:p.perm.width]
+	rate := p.perm.rate
+	for i := 0; i < len(input); i += rate {
+		copy(perm[:rate], input[i:])
+		p.PermuteSlice(perm)
+	}
+	return p.squeeze(perm, numOutputs)
+}
+
+func (p *Poseidon2[F, PF]) HashPad(input []F) [OUT]F {
+	res := p.HashNToMPad(input, OUT)
+	return [OUT]F{res[0], res[1], res[2], res[3]}
+}
+
+// HashNToMNoPad of the input with the 10*1 padding of plonky2's hash_pad: a one,
+// then zeros up to one element short of a multiple of Rate, then a final one.
+func (p *Poseidon2[F, PF]) HashNToMPad(input []F, numOutputs int) []F {
+	rate := p.perm.rate
+	padded := make([]F, len(input), (len(input)/rate+2)*rate)
+	copy(padded, input)
+	var one, zero F
+	PF(&one).SetOne()
+	padded = append(padded, one)
+	for (len(padded)+1)%rate != 0 {
+		padded = append(padded, zero)
+	}
+	padded = append(padded, one)
+	return p.HashNToMNoPad(padded, numOutputs)
+}
+
+func (p *Poseidon2[F, PF]) HashWithLength(input []F) [OUT]F {
+	res := p.HashNToMWithLength(input, OUT)
+	return [OUT]F{res[0], res[1], res[2], res[3]}
+}
+
+// Variable-length mode without padding: the length of the input is written to
+// the first capacity element before absorbing, which separates inputs of
+// different lengths. The state is permuted at least once, even for an empty
+// input. Unlike HashNToMPad, no extra block is absorbed when the length is a
+// multiple of Rate, but the outputs are not those of plonky2.
+func (p *Poseidon2[F, PF]) HashNToMWithLength(input []F, numOutputs int) []F {
+	var buf [MAX_WIDTH]F
+	perm := buf[:p.perm.width]
+	rate := p.perm.rate
+	PF(&perm[rate]).SetUint64(uint64(len(input)))
+	for i := 0; ; i += rate {
+		copy(perm[:rate], input[i:])
+		p.PermuteSlice(perm)
+		if i+rate >= len(input) {
+			break
+		}
+	}
+	return p.squeeze(perm, numOutputs)
+}
+
+// Reads numOutputs elements from the rate, permuting between blocks.
+func (p *Poseidon2[F, PF]) squeeze(perm []F, numOutputs int) []F {
+	outputs := make([]F, 0, numOutputs)
+	for {
+		for i := 0; i < p.perm.rate; i++ {
+			outputs = append(outputs, perm[i])
+			if len(outputs) == numOutputs {
+				return outputs
+			}
+		}
+		p.PermuteSlice(perm)
+	}
+}
+
+// Permutes input in place, for an instance of width WIDTH.
+func (p *Poseidon2[F, PF]) Permute(input *[WIDTH]F) {
+	p.PermuteSlice(input[:])
+}
+
+// Permutes state in place, through canonical GoldilocksField values: an Element
+// costs a Montgomery multiplication each way, little next to the hundreds of
+// the rounds, and every representation runs the same layers. state must have
+// Width elements. Outputs are canonical.
+func (p *Poseidon2[F, PF]) PermuteSlice(state []F) {
+	w := p.perm.width
+	if len(state) != w {
+		panic(fmt.Sprintf("state should have %d elements but has %d", w, len(state)))
+	}
+	var buf [MAX_WIDTH]g.GoldilocksField
+	s := buf[:w]
+	for i := range s {
+		s[i] = g.GoldilocksField(PF(&state[i]).Uint64())
+	}
+	p.perm.permute(s)
+	for i := range s {
+		PF(&state[i]).SetUint64(uint64(s[i]))
+	}
+}
@@ -0,0 +1,620 @@
+package poseidon2_generic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	"github.com/ppd0705/poseidon_crypto/field/matrix"
+)
+
+// Every representation of the Goldilocks field must pass the conformance tests.
+func forEachField(t *testing.T, f func(t *testing.T, impl implementation)) {
+	for _, impl := range implementations() {
+		impl := impl
+		t.Run(impl.name, func(t *testing.T) { f(t, impl) })
+	}
+}
+
+// Poseidon2 instance seen through canonical uint64 values.
+type implementation struct {
+	name             string
+	permute          func(state [WIDTH]uint64) [WIDTH]uint64
+	hashNToMNoPad    func(input []uint64, numOutputs int) []uint64
+	hashNToMPad      func(input []uint64, numOutputs int) []uint64
+	hashNToMLength   func(input []uint64, numOutputs int) []uint64
+	hashNToHashNoPad func(input []uint64) [OUT]uint64
+	hashTwoToOne     func(input1, input2 [OUT]uint64) [OUT]uint64
+	hashNToOne       func(input [][OUT]uint64) [OUT]uint64
+}
+
+func implementations() []implementation {
+	return []implementation{
+		newImplementation[g.Element]("Element"),
+		newImplementation[g.GoldilocksField]("GoldilocksField"),
+	}
+}
+
+func newImplementation[F any, PF g.Field[F]](name string) implementation {
+	p := New[F, PF]()
+
+	fromUint64 := func(in []uint64) []F {
+		res := make([]F, len(in))
+		for i := range in {
+			PF(&res[i]).SetUint64(in[i])
+		}
+		return res
+	}
+	toUint64 := func(in []F) []uint64 {
+		res := make([]uint64, len(in))
+		for i := range in {
+			res[i] = PF(&in[i]).Uint64()
+		}
+		return res
+	}
+	fromHash := func(in [OUT]uint64) (res [OUT]F) {
+		copy(res[:], fromUint64(in[:]))
+		return res
+	}
+	toHash := func(in [OUT]F) (res [OUT]uint64) {
+		copy(res[:], toUint64(in[:]))
+		return res
+	}
+
+	return implementation{
+		name: name,
+		permute: func(state [WIDTH]uint64) (res [WIDTH]uint64) {
+			var s [WIDTH]F
+			copy(s[:], fromUint64(state[:]))
+			p.Permute(&s)
+			copy(res[:], toUint64(s[:]))
+			return res
+		},
+		hashNToMNoPad: func(input []uint64, numOutputs int) []uint64 {
+			return toUint64(p.HashNToMNoPad(fromUint64(input), numOutputs))
+		},
+		hashNToMPad: func(input []uint64, numOutputs int) []uint64 {
+			return toUint64(p.HashNToMPad(fromUint64(input), numOutputs))
+		},
+		hashNToMLength: func(input []uint64, numOutputs int) []uint64 {
+			return toUint64(p.HashNToMWithLength(fromUint64(input), numOutputs))
+		},
+		hashNToHashNoPad: func(input []uint64) [OUT]uint64 {
+			return toHash(p.HashNToHashNoPad(fromUint64(input)))
+		},
+		hashTwoToOne: func(input1, input2 [OUT]uint64) [OUT]uint64 {
+			return toHash(p.HashTwoToOne(fromHash(input1), fromHash(input2)))
+		},
+		hashNToOne: func(input [][OUT]uint64) [OUT]uint64 {
+			in := make([][OUT]F, len(input))
+			for i := range input {
+				in[i] = fromHash(input[i])
+			}
+			return toHash(p.HashNToOne(in))
+		},
+	}
+}
+
+func TestPermute(t *testing.T) {
+	forEachField(t, func(t *testing.T, impl implementation) {
+		res := impl.permute([WIDTH]uint64{
+			5417613058500526590, 2481548824842427254, 6473243198879784792, 1720313757066167274,
+			2806320291675974571, 7407976414706455446, 1105257841424046885, 7613435757403328049,
+			3376066686066811538, 5888575799323675710, 6689309723188675948, 2468250420241012720,
+		})
+		expected := [WIDTH]uint64{
+			5364184781011389007, 15309475861242939136, 5983386513087443499, 886942118604446276,
+			14903657885227062600, 7742650891575941298, 1962182278500985790, 10213480816595178755,
+			3510799061817443836, 4610029967627506430, 7566382334276534836, 2288460879362380348,
+		}
+		if res != expected {
+			t.Fatalf("Expected %v, got %v", expected, res)
+		}
+	})
+}
+
+func TestHashNToMNoPad(t *testing.T) {
+	forEachField(t, func(t *testing.T, impl implementation) {
+		res := impl.hashNToMNoPad([]uint64{
+			2963773914414780088, 8389525300242074234, 3700959901615818008, 6116199383751757212,
+			3418607418699599889, 8793277256263635044, 448623437464918480, 1857310021116627925,
+			6145634616307237342, 1548353948794474539, 2318110128254703527, 8347759953730634762,
+		}, 12)
+		expected := []uint64{
+			3627923032009111551, 1460752551327577353, 1084214837491058067, 1841622875286057462,
+			3996252440506437984, 1276718204392552803, 8564515621134952155, 9252927025993202701,
+			1147435538714642916, 16407277821156164797, 11997661877740155273, 12485021000320141292,
+		}
+		for i := range expected {
+			if res[i] != expected[i] {
+				t.Fatalf("Expected %v, got %v", expected, res)
+			}
+		}
+	})
+}
+
+func TestHashNToHashNoPad(t *testing.T) {
+	forEachField(t, func(t *testing.T, impl implementation) {
+		res := impl.hashNToHashNoPad([]uint64{
+			11295517158488612626, 10669470463693797151, 17232114065640264171, 4175927072186299193,
+			13985285184240204531, 7901017084268693144, 4326299618263946178, 14787024750292535041,
+			894520636503353046, 12556655399058578835, 3097737892474696200, 7515335668060050861,
+		})
+		expected := [OUT]uint64{15396602476382546759, 12422280135166335470, 8165681190607828974, 3475588160239961712}
+		if res != expected {
+			t.Fatalf("Expected %v, got %v", expected, res)
+		}
+
+		// Inputs are reduced modulo the order.
+		res = impl.hashNToHashNoPad([]uint64{g.ORDER + 1, g.ORDER + 2, g.ORDER + 3, math.MaxUint64, math.MaxUint64 - 1})
+		expected = [OUT]uint64{14216040864787980138, 17275303675000904868, 11831395338463193314, 281267649235863375}
+		if res != expected {
+			t.Fatalf("Expected %v, got %v", expected, res)
+		}
+	})
+}
+
+func TestHashTwoToOne(t *testing.T) {
+	forEachField(t, func(t *testing.T, impl implementation) {
+		res := impl.hashTwoToOne(
+			[OUT]uint64{3777312593917610528, 6858608920877200812, 5269611035257552853, 10607733449481270434},
+			[OUT]uint64{10355703322562521155, 1039917189921776884, 10844249567941924238, 14291130953945924124},
+		)
+		expected := [OUT]uint64{1453933811752520343, 16186418140372484281, 9207215809524681813, 10182182911172027974}
+		if res != expected {
+			t.Fatalf("Expected %v, got %v", expected, res)
+		}
+	})
+}
+
+func TestHashNToOne(t *testing.T) {
+	forEachField(t, func(t *testing.T, impl implementation) {
+		hashIns := [][OUT]uint64{impl.hashNToHashNoPad([]uint64{
+			18231458557829081414, 16449039301999856654, 14758090268883299362, 10271725147130672875,
+			6253304685402495037, 16079709420464120062, 10838593640248082543, 2974225335734585509,
+			6365466669981419503, 12964544245312854826, 3161534615047618958, 15109271288782125222,
+		})}
+		for i := 1; i < 12; i++ {
+			hashIns = append(hashIns, impl.hashTwoToOne(hashIns[i-1], hashIns[i-1]))
+		}
+
+		res := impl.hashNToOne(hashIns)
+		expected := [OUT]uint64{3346041518891302234, 10181430332820953144, 14852547783810217847, 17043509806476508794}
+		if res != expected {
+			t.Fatalf("Expected %v, got %v", expected, res)
+		}
+
+		if impl.hashNToOne(hashIns[:1]) != hashIns[0] {
+			t.Fatalf("HashNToOne of a single hash should be the hash itself")
+		}
+	})
+}
+
+// All implementations agree on random inputs of every length around the rate.
+func TestImplementationsAgree(t *testing.T) {
+	impls := implementations()
+	rng := rand.New(rand.NewSource(0))
+	for n := 0; n <= 3*RATE; n++ {
+		input := make([]uint64, n)
+		for i := range input {
+			input[i] = rng.Uint64()
+		}
+		for _, mode := range []func(impl implementation) func([]uint64, int) []uint64{
+			func(impl implementation) func([]uint64, int) []uint64 { return impl.hashNToMNoPad },
+			func(impl implementation) func([]uint64, int) []uint64 { return impl.hashNToMPad },
+			func(impl implementation) func([]uint64, int) []uint64 { return impl.hashNToMLength },
+		} {
+			expected := mode(impls[0])(input, 2*RATE+1)
+			for _, impl := range impls[1:] {
+				res := mode(impl)(input, 2*RATE+1)
+				for i := range expected {
+					if res[i] != expected[i] {
+						t.Fatalf("%s and %s disagree on input %v", impls[0].name, impl.name, input)
+					}
+				}
+			}
+		}
+	}
+}
+
+// HashNToMPad is HashNToMNoPad of the input padded by hand as in plonky2's
+// hash_pad.
+func TestHashNToMPad(t *testing.T) {
+	forEachField(t, func(t *testing.T, impl implementation) {
+		for n := 0; n <= 3*RATE; n++ {
+			input := make([]uint64, n)
+			for i := range input {
+				input[i] = uint64(i + 1)
+			}
+			padded := append(append([]uint64(nil), input...), 1)
+			for (len(padded)+1)%RATE != 0 {
+				padded = append(padded, 0)
+			}
+			padded = append(padded, 1)
+
+			expected := impl.hashNToMNoPad(padded, OUT)
+			res := impl.hashNToMPad(input, OUT)
+			for i := range expected {
+				if res[i] != expected[i] {
+					t.Fatalf("length %d: expected %v, got %v", n, expected, res)
+				}
+			}
+		}
+	})
+}
+
+// Appending zeros changes the padded hashes, unlike the unpadded one.
+func TestPaddingSeparatesLengths(t *testing.T) {
+	forEachField(t, func(t *testing.T, impl implementation) {
+		modes := map[string]func([]uint64, int) []uint64{
+			"Pad":        impl.hashNToMPad,
+			"WithLength": impl.hashNToMLength,
+		}
+		for name, hash := range modes {
+			seen := map[[OUT]uint64]int{}
+			for n := 0; n <= 3*RATE; n++ {
+				input := make([]uint64, n)
+				if n > 0 {
+					input[0] = 7
+				}
+				var res [OUT]uint64
+				copy(res[:], hash(input, OUT))
+				if m, ok := seen[res]; ok {
+					t.Fatalf("%s: lengths %d and %d collide", name, m, n)
+				}
+				seen[res] = n
+				if res == ([OUT]uint64{}) {
+					t.Fatalf("%s: length %d hashes to zero", name, n)
+				}
+			}
+		}
+
+		if impl.hashNToHashNoPad([]uint64{7}) != impl.hashNToHashNoPad([]uint64{7, 0}) {
+			t.Fatalf("HashNToHashNoPad was expected to ignore trailing zeros")
+		}
+	})
+}
+
+func BenchmarkPermute(b *testing.B) {
+	b.Run("Element", benchmarkPermute[g.Element])
+	b.Run("GoldilocksField", benchmarkPermute[g.GoldilocksField])
+}
+
+func benchmarkPermute[F any, PF g.Field[F]](b *testing.B) {
+	p := New[F, PF]()
+	var state [WIDTH]F
+	for i := range state {
+		PF(&state[i]).SetUint64(uint64(i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Permute(&state)
+	}
+}
+
+func randomState[F any, PF g.Field[F]](rng *rand.Rand) [WIDTH]F {
+	var state [WIDTH]F
+	for i := range state {
+		PF(&state[i]).SetUint64(rng.Uint64())
+	}
+	return state
+}
+
+func externalLinearLayer(s *[WIDTH]g.GoldilocksField) {
+	plonky3.externalLinearLayer(s[:])
+}
+
+func internalLinearLayer(s *[WIDTH]g.GoldilocksField) {
+	plonky3.internalLinearLayer(s[:])
+}
+
+// The linear layers reducing after every operation, as in Plonky3.
+func externalLinearLayerReduced(s *[WIDTH]g.GoldilocksField) {
+	for i := 0; i < WIDTH; i += 4 {
+		t0 := g.AddF(s[i], s[i+1])   // s0+s1
+		t1 := g.AddF(s[i+2], s[i+3]) // s2+s3
+		t2 := g.AddF(t0, t1)         // s0+s1+s2+s3
+		t3 := g.AddF(t2, s[i+1])     // s0+2s1+s2+s3
+		t4 := g.AddF(t2, s[i+3])     // s0+s1+s2+2s3
+		t5 := g.DoubleF(s[i])        // 2s0
+		t6 := g.DoubleF(s[i+2])      // 2s2
+		s[i] = g.AddF(t3, t0)
+		s[i+1] = g.AddF(t6, t3)
+		s[i+2] = g.AddF(t1, t4)
+		s[i+3] = g.AddF(t5, t4)
+	}
+
+	var sums [4]g.GoldilocksField
+	for k := 0; k < 4; k++ {
+		for j := 0; j < WIDTH; j += 4 {
+			sums[k] = g.AddF(sums[k], s[j+k])
+		}
+	}
+	for i := range s {
+		s[i] = g.AddF(s[i], sums[i%4])
+	}
+}
+
+func internalLinearLayerReduced(s *[WIDTH]g.GoldilocksField) {
+	sum := s[0]
+	for i := 1; i < WIDTH; i++ {
+		sum = g.AddF(sum, s[i])
+	}
+	for i := range s {
+		s[i] = g.AddF(g.MulF(s[i], g.GoldilocksField(MATRIX_DIAG_12_U64[i])), sum)
+	}
+}
+
+func TestLazyLinearLayers(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	for iter := 0; iter < 1000; iter++ {
+		state := randomState[g.GoldilocksField](rng)
+		if iter%2 == 0 {
+			// Non-canonical values, up to 2^64 - 1, as the s-box may return.
+			for i := range state {
+				state[i] = g.GoldilocksField(math.MaxUint64 - rng.Uint64()%(1<<33))
+			}
+		}
+
+		got, want := state, state
+		externalLinearLayer(&got)
+		externalLinearLayerReduced(&want)
+		internalLinearLayer(&got)
+		internalLinearLayerReduced(&want)
+		for i := range got {
+			if got[i].ToCanonicalUint64() != want[i].ToCanonicalUint64() {
+				t.Fatalf("layers differ at %d for %v", i, state)
+			}
+		}
+	}
+}
+
+func BenchmarkLinearLayers(b *testing.B) {
+	b.Run("Lazy", func(b *testing.B) {
+		benchmarkLinearLayers(b, externalLinearLayer, internalLinearLayer)
+	})
+	b.Run("Reduced", func(b *testing.B) {
+		benchmarkLinearLayers(b, externalLinearLayerReduced, internalLinearLayerReduced)
+	})
+}
+
+// One external and one internal layer.
+func benchmarkLinearLayers(b *testing.B, external, internal func(*[WIDTH]g.GoldilocksField)) {
+	state := randomState[g.GoldilocksField](rand.New(rand.NewSource(0)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		external(&state)
+		internal(&state)
+	}
+}
+
+func linearLayerMatrix(layer func(*[WIDTH]g.GoldilocksField)) matrix.Matrix {
+	return matrix.FromLinearMap(WIDTH, func(v []g.GoldilocksField) []g.GoldilocksField {
+		var state [WIDTH]g.GoldilocksField
+		copy(state[:], v)
+		layer(&state)
+		return state[:]
+	})
+}
+
+func TestInternalLinearLayer(t *testing.T) {
+	m := linearLayerMatrix(internalLinearLayer)
+
+	// M_I = J + diag(MATRIX_DIAG_12), with J the all-ones matrix.
+	want := matrix.New(WIDTH, WIDTH)
+	for i := range want {
+		for j := range want[i] {
+			want[i][j] = g.OneF()
+		}
+		want[i][i] = g.AddF(want[i][i], g.GoldilocksField(MATRIX_DIAG_12_U64[i]))
+	}
+	if !m.Equals(want) {
+		t.Fatal("internal layer does not match 1 + MATRIX_DIAG_12")
+	}
+	if !m.IsInvertible() {
+		t.Fatal("internal matrix is singular")
+	}
+	if !m.IsInvariantSubspaceFree() {
+		t.Fatal("internal matrix admits invariant subspace trails")
+	}
+}
+
+func TestExternalLinearLayer(t *testing.T) {
+	m := linearLayerMatrix(externalLinearLayer)
+
+	// M_E = circ(2 M4, M4, M4) on 4x4 blocks, with Plonky3's M4 = circ(2, 3, 1, 1)
+	// rather than the one from the paper.
+	m4 := matrix.Circulant([]g.GoldilocksField{2, 3, 1, 1})
+	for i := range m {
+		for j := range m[i] {
+			want := m4[i%4][j%4]
+			if i/4 == j/4 {
+				want = g.DoubleF(want)
+			}
+			if m[i][j] != want {
+				t.Fatalf("external matrix entry (%d, %d) is %d, want %d", i, j, m[i][j], want)
+			}
+		}
+	}
+	if !m4.IsMDS() {
+		t.Fatal("M4 is not MDS")
+	}
+	if !m.IsInvertible() {
+		t.Fatal("external matrix is singular")
+	}
+	// Poseidon2 does not need M_E to be MDS, and it is not: the 2x2 minors
+	// across two off-diagonal blocks repeat the same entries.
+	if m.IsMDS() {
+		t.Fatal("external matrix is MDS")
+	}
+}
+
+func TestSponge(t *testing.T) {
+	t.Run("Element", testSponge[g.Element])
+	t.Run("GoldilocksField", testSponge[g.GoldilocksField])
+}
+
+func testSponge[F any, PF g.Field[F]](t *testing.T) {
+	for _, params := range []*Poseidon2Params{PARAMS_12_PLONKY3, PARAMS_8, PARAMS_20} {
+		p, err := NewWithParams[F, PF](params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testSpongeInstance(t, p)
+	}
+}
+
+func testSpongeInstance[F any, PF g.Field[F]](t *testing.T, p *Poseidon2[F, PF]) {
+	rng := rand.New(rand.NewSource(21))
+	rate := p.Params().Rate
+	equal := func(a, b []F) bool {
+		for i := range a {
+			if !PF(&a[i]).Equal(&b[i]) {
+				return false
+			}
+		}
+		return len(a) == len(b)
+	}
+
+	for n := 0; n <= 3*rate+1; n++ {
+		input := make([]F, n)
+		for i := range input {
+			PF(&input[i]).SetUint64(rng.Uint64())
+		}
+		numOutputs := 2*rate + 3
+		want := p.HashNToMNoPad(input, numOutputs)
+
+		// Absorb and squeeze in random chunks.
+		s := p.NewSponge()
+		for rest := input; len(rest) > 0; {
+			k := rng.Intn(len(rest) + 1)
+			s.Absorb(rest[:k]...)
+			rest = rest[k:]
+		}
+		var got []F
+		for len(got) < numOutputs {
+			k := rng.Intn(numOutputs - len(got) + 1)
+			got = append(got, s.Squeeze(k)...)
+		}
+		if !equal(got, want) {
+			t.Fatalf("width %d, %d inputs: sponge differs from HashNToMNoPad", p.Params().Width, n)
+		}
+
+		s.Reset()
+		s.Absorb(input...)
+		if got := s.Squeeze(OUT); !equal(got, want[:OUT]) {
+			t.Fatalf("width %d, %d inputs: sponge differs after Reset", p.Params().Width, n)
+		}
+	}
+}
+
+func TestSpongeDuplex(t *testing.T) {
+	p := New[g.GoldilocksField]()
+	s := p.NewSponge()
+	s.Absorb(1, 2, 3)
+	first := s.Squeeze(2)
+
+	// A copy continues independently.
+	fork := *s
+	s.Absorb(4, 5)
+	second := s.Squeeze(3)
+	if got := fork.Squeeze(2); got[0] != p.HashNToMNoPad([]g.GoldilocksField{1, 2, 3}, 4)[2] {
+		t.Fatalf("fork does not continue the output stream, got %v", got)
+	}
+
+	// Absorbing after squeezing overwrites the start of the rate, then permutes.
+	var state [WIDTH]g.GoldilocksField
+	state[0], state[1], state[2] = 1, 2, 3
+	p.Permute(&state)
+	if state[0] != first[0] || state[1] != first[1] {
+		t.Fatal("first squeeze does not read the permuted state")
+	}
+	state[0], state[1] = 4, 5
+	p.Permute(&state)
+	for i := range second {
+		if second[i] != state[i] {
+			t.Fatalf("duplexed output %d is %d, want %d", i, second[i], state[i])
+		}
+	}
+}
+
+func TestSpongeConstantMemory(t *testing.T) {
+	p := New[g.GoldilocksField]()
+	s := p.NewSponge()
+	block := make([]g.GoldilocksField, 1000)
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Absorb(block...)
+	})
+	if allocs != 0 {
+		t.Fatalf("Absorb allocates %v times per call", allocs)
+	}
+	var out [OUT]g.GoldilocksField
+	allocs = testing.AllocsPerRun(100, func() {
+		s.SqueezeInto(out[:])
+	})
+	if allocs != 0 {
+		t.Fatalf("SqueezeInto allocates %v times per call", allocs)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	t.Run("Element", testDigest[g.Element])
+	t.Run("GoldilocksField", testDigest[g.GoldilocksField])
+}
+
+func testDigest[F any, PF g.Field[F]](t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	p := New[F, PF]()
+	for n := 0; n <= 2*g.PackedBytesPerElement*RATE+1; n++ {
+		data := make([]byte, n)
+		rng.Read(data)
+
+		// PackBytesF with the length moved to the end.
+		packed := g.PackBytesF(data)
+		input := make([]F, len(packed))
+		for i, x := range append(packed[1:], packed[0]) {
+			PF(&input[i]).SetUint64(uint64(x))
+		}
+		var want []byte
+		for _, elem := range p.HashNToHashNoPad(input) {
+			want = binary.LittleEndian.AppendUint64(want, PF(&elem).Uint64())
+		}
+
+		// Write in random chunks, splitting elements.
+		d := p.NewDigest()
+		for rest := data; len(rest) > 0; {
+			k := rng.Intn(len(rest) + 1)
+			if _, err := d.Write(rest[:k]); err != nil {
+				t.Fatal(err)
+			}
+			rest = rest[k:]
+		}
+		if got := d.Sum(nil); !bytes.Equal(got, want) {
+			t.Fatalf("%d bytes: digest differs from HashNToHashNoPad of the packed bytes", n)
+		}
+	}
+
+	// Any bytes are accepted, also those of a non-canonical element.
+	d := p.NewDigest()
+	nonCanonical := binary.LittleEndian.AppendUint64(nil, g.ORDER)
+	if n, err := d.Write(nonCanonical); n != len(nonCanonical) || err != nil {
+		t.Fatalf("Write returned %d, %v", n, err)
+	}
+	d2 := p.NewDigest()
+	d2.Write(binary.LittleEndian.AppendUint64(nil, 0))
+	if bytes.Equal(d.Sum(nil), d2.Sum(nil)) {
+		t.Fatal("ORDER and 0 should not hash the same")
+	}
+
+	// Trailing zero bytes change the digest.
+	d.Reset()
+	d.Write([]byte("a"))
+	d2.Reset()
+	d2.Write([]byte("a\x00"))
+	if bytes.Equal(d.Sum(nil), d2.Sum(nil)) {
+		t.Fatal(`"a" and "a\x00" should not hash the same`)
+	}
+}
@@ -0,0 +1,75 @@
+package poseidon2_generic
+
+import (
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+// Incremental form of HashNToMNoPad: absorbing the elements of an input over any
+// number of calls and then squeezing gives the same outputs, while only the
+// state is kept in memory. The input overwrites the rate part of the state,
+// which is permuted as soon as it is full.
+//
+// Absorbing after squeezing duplexes: the new elements overwrite the rate from
+// its start, and the next Squeeze permutes before reading. A Sponge is a plain
+// value, so a copy continues independently from the same point.
+type Sponge[F any, PF g.Field[F]] struct {
+	p         *Poseidon2[F, PF]
+	state     [MAX_WIDTH]F // the first Width elements
+	absorbed  int          // elements written to the rate since the last permutation
+	squeezed  int          // elements read from the rate since the last permutation
+	squeezing bool
+}
+
+func (p *Poseidon2[F, PF]) NewSponge() *Sponge[F, PF] {
+	return &Sponge[F, PF]{p: p}
+}
+
+func (s *Sponge[F, PF]) Reset() {
+	*s = Sponge[F, PF]{p: s.p}
+}
+
+func (s *Sponge[F, PF]) Absorb(elems ...F) {
+	if len(elems) == 0 {
+		return
+	}
+	s.squeezing = false
+	for len(elems) > 0 {
+		n := copy(s.state[s.absorbed:s.p.perm.rate], elems)
+		s.absorbed += n
+		elems = elems[n:]
+		if s.absorbed == s.p.perm.rate {
+			s.permute()
+			s.absorbed = 0
+		}
+	}
+}
+
+// Returns the next n output elements.
+func (s *Sponge[F, PF]) Squeeze(n int) []F {
+	return s.SqueezeInto(make([]F, n))
+}
+
+// Fills out with the next output elements and returns it.
+func (s *Sponge[F, PF]) SqueezeInto(out []F) []F {
+	if !s.squeezing {
+		if s.absorbed > 0 {
+			s.permute()
+			s.absorbed = 0
+		}
+		s.squeezing = true
+		s.squeezed = 0
+	}
+	for i := range out {
+		if s.squeezed == s.p.perm.rate {
+			s.permute()
+			s.squeezed = 0
+		}
+		out[i] = s.state[s.squeezed]
+		s.squeezed++
+	}
+	return out
+}
+
+func (s *Sponge[F, PF]) permute() {
+	s.p.PermuteSlice(s.state[:s.p.perm.width])
+}
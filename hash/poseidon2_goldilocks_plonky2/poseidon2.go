@@ -6,6 +6,7 @@ import (
 
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
 	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
+	poseidon2_generic "github.com/ppd0705/poseidon_crypto/hash/poseidon2_goldilocks_generic"
 )
 
 type HashOut [4]g.GoldilocksField
@@ -27,12 +28,13 @@ func HashOutFromLittleEndianBytes(b []byte) (HashOut, error) {
 	if len(b) != 4*g.Bytes {
 		return HashOut{}, fmt.Errorf("input bytes len should be 32 but is %d", len(b))
 	}
-	var res HashOut
-	for i := 0; i < 4; i++ {
-		res[i] = g.FromCanonicalLittleEndianBytesF(b[i*g.Bytes : (i+1)*g.Bytes])
+
+	gArr, err := g.ArrayFromCanonicalLittleEndianBytesF(b)
+	if err != nil {
+		return HashOut{}, fmt.Errorf("failed to convert bytes to field element. bytes: %v, error: %w", b, err)
 	}
 
-	return res, nil
+	return HashOut{gArr[0], gArr[1], gArr[2], gArr[3]}, nil
 }
 
 func (h HashOut) ToUint64Array() [4]uint64 {
@@ -55,208 +57,91 @@ func HashNoPad(input []g.GoldilocksField) HashOut {
 }
 
 func HashNToOne(input []HashOut) HashOut {
-	if len(input) == 1 {
-		return input[0]
-	}
-
-	res := HashTwoToOne(input[0], input[1])
-	for i := 2; i < len(input); i++ {
-		res = HashTwoToOne(res, input[i])
-	}
-
-	return res
+	return poseidon2_generic.HashNToOne(permutation, input)
 }
 
 func HashTwoToOne(input1, input2 HashOut) HashOut {
-	return HashNToHashNoPad([]g.GoldilocksField{input1[0], input1[1], input1[2], input1[3], input2[0], input2[1], input2[2], input2[3]})
+	return permutation.HashTwoToOne(input1, input2)
 }
 
 func HashNToHashNoPad(input []g.GoldilocksField) HashOut {
-	res := HashNToMNoPad(input, 4)
-	return HashOut{res[0], res[1], res[2], res[3]}
+	return permutation.HashNToHashNoPad(input)
 }
 
 func HashNToMNoPad(input []g.GoldilocksField, numOutputs int) []g.GoldilocksField {
-	var perm [WIDTH]g.GoldilocksField
-	for i := 0; i < len(input); i += RATE {
-		for j := 0; j < RATE && i+j < len(input); j++ {
-			perm[j] = input[i+j]
-		}
-		Permute(&perm)
-	}
-
-	outputs := make([]g.GoldilocksField, 0, numOutputs)
-	for {
-		for i := 0; i < RATE; i++ {
-			outputs = append(outputs, perm[i])
-			if len(outputs) == numOutputs {
-				return outputs
-			}
-		}
-		Permute(&perm)
-	}
+	return permutation.HashNToMNoPad(input, numOutputs)
 }
 
-func HashNToMNoPadBytes(input []byte, numOutputs int) []g.GoldilocksField {
-	if len(input)%g.Bytes != 0 {
-		panic("input length should be multiple of 8")
-	}
-
-	inputLen := len(input) / g.Bytes
-
-	var perm [WIDTH]g.GoldilocksField
-	for i := 0; i < inputLen; i += RATE {
-		for j := 0; j < RATE && i+j < inputLen; j++ {
-			index := (i + j) * g.Bytes
-			perm[j] = g.FromCanonicalLittleEndianBytesF(input[index : index+g.Bytes])
-		}
-		Permute(&perm)
-	}
-
-	outputs := make([]g.GoldilocksField, 0, numOutputs)
-	for {
-		for i := 0; i < RATE; i++ {
-			outputs = append(outputs, perm[i])
-			if len(outputs) == numOutputs {
-				return outputs
-			}
-		}
-		Permute(&perm)
-	}
+// Variable-length hashing with the 10*1 padding of plonky2's hash_pad, see
+// poseidon2_generic.Poseidon2.HashNToMPad.
+func HashPad(input []g.GoldilocksField) HashOut {
+	return permutation.HashPad(input)
 }
 
-func Permute(input *[WIDTH]g.GoldilocksField) {
-	externalLinearLayer(input)
-	fullRounds(input, 0)
-	partialRounds(input)
-	fullRounds(input, ROUNDS_F_HALF)
+func HashNToMPad(input []g.GoldilocksField, numOutputs int) []g.GoldilocksField {
+	return permutation.HashNToMPad(input, numOutputs)
 }
 
-func fullRounds(state *[WIDTH]g.GoldilocksField, start int) {
-	for r := start; r < start+ROUNDS_F_HALF; r++ {
-		addRC(state, r)
-		sbox(state)
-		externalLinearLayer(state)
-	}
+// Variable-length hashing with the input length in the capacity, see
+// poseidon2_generic.Poseidon2.HashNToMWithLength.
+func HashWithLength(input []g.GoldilocksField) HashOut {
+	return permutation.HashWithLength(input)
 }
 
-func partialRounds(state *[WIDTH]g.GoldilocksField) {
-	for r := 0; r < ROUNDS_P; r++ {
-		addRCI(state, r)
-		sboxP(0, state)
-		internalLinearLayer(state)
-	}
+func HashNToMWithLength(input []g.GoldilocksField, numOutputs int) []g.GoldilocksField {
+	return permutation.HashNToMWithLength(input, numOutputs)
 }
 
-func externalLinearLayer(s *[WIDTH]g.GoldilocksField) {
-	for i := 0; i < 3; i++ { // 4 size window
-		var t0, t1, t2, t3, t4, t5, t6 g.GoldilocksField
-		t0 = g.AddF(s[4*i], s[4*i+1])   // s0+s1
-		t1 = g.AddF(s[4*i+2], s[4*i+3]) // s2+s3
-		t2 = g.AddF(t0, t1)             // t0+t1 = s0+s1+s2+s3
-		t3 = g.AddF(t2, s[4*i+1])       // t2+s1 = s0+2s1+s2+s3
-		t4 = g.AddF(t2, s[4*i+3])       // t2+s3 = s0+s1+s2+2s3
-		t5 = g.DoubleF(s[4*i])          // 2s0
-		t6 = g.DoubleF(s[4*i+2])        // 2s2
-		s[4*i] = g.AddF(t3, t0)
-		s[4*i+1] = g.AddF(t6, t3)
-		s[4*i+2] = g.AddF(t1, t4)
-		s[4*i+3] = g.AddF(t5, t4)
-	}
-
-	sums := [4]g.GoldilocksField{}
-	for k := 0; k < 4; k++ {
-		for j := 0; j < WIDTH; j += 4 {
-			sums[k] = g.AddF(sums[k], s[j+k])
-		}
-	}
-	for i := 0; i < WIDTH; i++ {
-		s[i] = g.AddF(s[i], sums[i%4])
-	}
+// Hashes an arbitrary byte string. The bytes are packed with g.PackBytesF, which
+// prefixes their length, so distinct strings never hash the same elements.
+func HashBytes(input []byte) HashOut {
+	return HashNToHashNoPad(g.PackBytesF(input))
 }
 
-func internalLinearLayer(state *[WIDTH]g.GoldilocksField) {
-	sum := state[0]
-	for i := 1; i < WIDTH; i++ {
-		sum = g.AddF(sum, state[i])
+// Hashes the concatenation of 8-byte little-endian field element encodings,
+// without checking that they are canonical. It panics if the length of the
+// input is not a multiple of 8.
+//
+// Deprecated: use HashNToMNoPadBytesStrict, which rejects non-canonical
+// encodings and returns an error instead of panicking.
+func HashNToMNoPadBytes(input []byte, numOutputs int) []g.GoldilocksField {
+	if len(input)%g.Bytes != 0 {
+		panic("input length should be multiple of 8")
 	}
-	for i := 0; i < WIDTH; i++ {
-		state[i] = g.MulF(state[i], MATRIX_DIAG_12_U64[i])
-		state[i] = g.AddF(state[i], sum)
+	elems := make([]g.GoldilocksField, len(input)/g.Bytes)
+	for i := range elems {
+		elems[i] = g.FromCanonicalLittleEndianBytesF(input[i*g.Bytes : (i+1)*g.Bytes])
 	}
+	return HashNToMNoPad(elems, numOutputs)
 }
 
-func addRC(state *[WIDTH]g.GoldilocksField, externalRound int) {
-	for i := 0; i < WIDTH; i++ {
-		state[i] = g.AddF(state[i], EXTERNAL_CONSTANTS[externalRound][i])
+// Hashes the concatenation of canonical 8-byte little-endian field element
+// encodings. Non-canonical encodings are rejected.
+func HashNToMNoPadBytesStrict(input []byte, numOutputs int) ([]g.GoldilocksField, error) {
+	elems, err := g.ArrayFromCanonicalLittleEndianBytesF(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert bytes to field elements: %w", err)
 	}
+	return HashNToMNoPad(elems, numOutputs), nil
 }
 
-func addRCI(state *[WIDTH]g.GoldilocksField, round int) {
-	state[0] = g.AddF(state[0], INTERNAL_CONSTANTS[round])
-}
-
-func sbox(state *[WIDTH]g.GoldilocksField) {
-	for i := range state {
-		sboxP(i, state)
-	}
+func Permute(input *[WIDTH]g.GoldilocksField) {
+	permutation.Permute(input)
 }
 
-func sboxP(index int, state *[WIDTH]g.GoldilocksField) {
-	tmp := state[index]
-	tmpSquare := g.SquareF(tmp)
-
-	var tmpSixth g.GoldilocksField
-	tmpSixth = g.MulF(tmpSquare, tmp)
-	tmpSixth = g.SquareF(tmpSixth)
+// Streaming HashNToMNoPad, see poseidon2_generic.Sponge.
+type Sponge = poseidon2_generic.Sponge[g.GoldilocksField, *g.GoldilocksField]
 
-	state[index] = g.MulF(tmpSixth, tmp)
+func NewSponge() *Sponge {
+	return permutation.NewSponge()
 }
 
-const BlockSize = g.Bytes * WIDTH // BlockSize size that poseidon consumes
-
-type digest struct {
-	data []byte
-	len  int
-}
+const (
+	Size      = g.Bytes * OUT                  // Size of a digest in bytes
+	BlockSize = g.PackedBytesPerElement * RATE // BlockSize size that poseidon consumes per permutation
+)
 
+// hash.Hash over byte strings, see poseidon2_generic.Digest.
 func NewPoseidon2() hash.Hash {
-	d := new(digest)
-	return d
-}
-
-// Reset resets the Hash to its initial state.
-func (d *digest) Reset() {
-	d.data = d.data[:0]
-	d.len = 0
-}
-
-// Get element by element.
-func (d *digest) Write(p []byte) (n int, err error) {
-	d.data = append(d.data, p...)
-	d.len += len(p)
-
-	return len(p), nil
-}
-
-// Sum appends the current hash to b and returns the resulting slice.
-// It does not change the underlying hash state.
-func (d *digest) Sum(b []byte) []byte {
-	h := HashNToMNoPadBytes(d.data, 4)
-	d.Reset()
-
-	for _, elem := range h {
-		b = append(b, g.ToLittleEndianBytesF(elem)...)
-	}
-
-	return b
-}
-
-func (d *digest) Size() int {
-	return BlockSize
-}
-
-// BlockSize returns the number of bytes Sum will return.
-func (d *digest) BlockSize() int {
-	return BlockSize
+	return permutation.NewDigest()
 }
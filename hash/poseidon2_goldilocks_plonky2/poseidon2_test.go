@@ -2,7 +2,10 @@ package poseidon2_plonky2
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
 	"math"
+	"math/rand"
 	"testing"
 
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
@@ -112,15 +115,16 @@ func TestDigest(t *testing.T) {
 	inputs[1][6] = 1
 	inputs[1][7] = 0
 
-	g1 := g.FromCanonicalLittleEndianBytesF(inputs[0]) // 289077004332300282
-	g2 := g.FromCanonicalLittleEndianBytesF(inputs[1]) // 289644378102298614
+	// The packed bytes, with their length moved to the end.
+	packed := g.PackBytesF(append(append([]byte(nil), inputs[0]...), inputs[1]...))
+	elems := append(packed[1:], packed[0])
 
 	hFunc.Write(inputs[0])
 	hFunc.Write(inputs[1])
 
 	hash := hFunc.Sum(nil)
 
-	hash2Elems := HashNoPad([]g.GoldilocksField{g1, g2})
+	hash2Elems := HashNoPad(elems)
 	hash2 := hash2Elems.ToLittleEndianBytes()
 
 	if !bytes.Equal(hash, hash2) {
@@ -142,6 +146,149 @@ func TestDigest(t *testing.T) {
 	}
 }
 
+func TestDigestStreaming(t *testing.T) {
+	input := make([]g.GoldilocksField, 3*RATE+5)
+	var buf []byte
+	for i := range input {
+		input[i] = g.GoldilocksField(uint64(i) * 0x9e3779b97f4a7c15 % g.ORDER)
+		buf = append(buf, g.ToLittleEndianBytesF(input[i])...)
+	}
+	packed := g.PackBytesF(buf)
+	expected := HashNoPad(append(packed[1:], packed[0])).ToLittleEndianBytes()
+
+	for _, chunk := range []int{1, 3, 7, 8, 13, 64, 100, len(buf)} {
+		hFunc := NewPoseidon2()
+		for i := 0; i < len(buf); i += chunk {
+			end := i + chunk
+			if end > len(buf) {
+				end = len(buf)
+			}
+			if _, err := hFunc.Write(buf[i:end]); err != nil {
+				t.Fatalf("chunk %d: %v", chunk, err)
+			}
+		}
+		if hash := hFunc.Sum(nil); !bytes.Equal(hash, expected) {
+			t.Fatalf("chunk %d: expected %v, got %v", chunk, expected, hash)
+		}
+	}
+
+}
+
+func TestNonCanonicalBytesRejected(t *testing.T) {
+	canonical := make([]byte, 4*g.Bytes)
+	binary.LittleEndian.PutUint64(canonical[8:], 42)
+
+	// Same elements as canonical, with the second one encoded as 42 + ORDER.
+	nonCanonical := make([]byte, 4*g.Bytes)
+	binary.LittleEndian.PutUint64(nonCanonical[8:], 42+g.ORDER)
+
+	if _, err := HashOutFromLittleEndianBytes(canonical); err != nil {
+		t.Fatalf("Expected canonical hash out to be accepted, got error: %v", err)
+	}
+	if _, err := HashOutFromLittleEndianBytes(nonCanonical); err == nil {
+		t.Fatalf("Expected non-canonical hash out to be rejected")
+	}
+
+	strict, err := HashNToMNoPadBytesStrict(canonical, 4)
+	if err != nil {
+		t.Fatalf("Expected canonical input to be hashed, got error: %v", err)
+	}
+	for i, elem := range HashNToMNoPadBytes(canonical, 4) {
+		if elem != strict[i] {
+			t.Fatalf("Deprecated HashNToMNoPadBytes differs at %d", i)
+		}
+	}
+	if _, err := HashNToMNoPadBytesStrict(nonCanonical, 4); err == nil {
+		t.Fatalf("Expected non-canonical input to be rejected")
+	}
+	if _, err := HashNToMNoPadBytesStrict(canonical[:7], 4); err == nil {
+		t.Fatalf("Expected input of 7 bytes to be rejected")
+	}
+
+	// The digest hashes bytes, not elements: it accepts any of them, and 42 and
+	// 42 + ORDER give different digests.
+	hFunc := NewPoseidon2()
+	if n, err := hFunc.Write(nonCanonical); n != len(nonCanonical) || err != nil {
+		t.Fatalf("Write returned %d, %v", n, err)
+	}
+	other := NewPoseidon2()
+	other.Write(canonical)
+	if bytes.Equal(hFunc.Sum(nil), other.Sum(nil)) {
+		t.Fatalf("Expected non-canonical bytes to give another digest")
+	}
+}
+
+// Checks of the hash.Hash contract, in the style of the standard library's.
+func TestHashConformance(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	msg := make([]byte, 3*BlockSize+2*g.Bytes)
+	rng.Read(msg)
+
+	h := NewPoseidon2()
+	if h.Size() != Size || h.BlockSize() != BlockSize {
+		t.Fatalf("expected sizes %d and %d, got %d and %d", Size, BlockSize, h.Size(), h.BlockSize())
+	}
+
+	n, err := h.Write(msg)
+	if n != len(msg) || err != nil {
+		t.Fatalf("Write returned %d, %v", n, err)
+	}
+	sum := h.Sum(nil)
+	if len(sum) != h.Size() {
+		t.Fatalf("expected %d bytes, got %d", h.Size(), len(sum))
+	}
+
+	// Sum appends to its argument.
+	prefix := []byte("prefix")
+	withPrefix := h.Sum(append([]byte(nil), prefix...))
+	if !bytes.Equal(withPrefix[:len(prefix)], prefix) || !bytes.Equal(withPrefix[len(prefix):], sum) {
+		t.Fatalf("expected %x appended to %x, got %x", sum, prefix, withPrefix)
+	}
+
+	// Sum leaves the state alone: summing again or writing more behaves as if
+	// it had not been called.
+	if again := h.Sum(nil); !bytes.Equal(again, sum) {
+		t.Fatalf("second Sum: expected %x, got %x", sum, again)
+	}
+	h.Write(msg)
+	oneShot := NewPoseidon2()
+	oneShot.Write(append(append([]byte(nil), msg...), msg...))
+	if got, expected := h.Sum(nil), oneShot.Sum(nil); !bytes.Equal(got, expected) {
+		t.Fatalf("Write after Sum: expected %x, got %x", expected, got)
+	}
+
+	// Writes can be split anywhere.
+	for _, chunk := range []int{1, 5, g.Bytes + 3, BlockSize - 1} {
+		h.Reset()
+		for rest := msg; len(rest) > 0; {
+			n := chunk
+			if n > len(rest) {
+				n = len(rest)
+			}
+			h.Write(rest[:n])
+			rest = rest[n:]
+		}
+		if got := h.Sum(nil); !bytes.Equal(got, sum) {
+			t.Fatalf("chunk %d: expected %x, got %x", chunk, sum, got)
+		}
+	}
+
+	// Trailing zero bytes are part of the input.
+	h.Reset()
+	h.Write(msg[:len(msg)-3])
+	partial := h.Sum(nil)
+	h.Write([]byte{0, 0, 0})
+	if got := h.Sum(nil); bytes.Equal(got, partial) {
+		t.Fatalf("trailing zero bytes: expected another digest than %x", partial)
+	}
+
+	// Reset goes back to the initial state.
+	h.Reset()
+	if got, expected := h.Sum(nil), NewPoseidon2().Sum(nil); !bytes.Equal(got, expected) {
+		t.Fatalf("Reset: expected %x, got %x", expected, got)
+	}
+}
+
 func TestHashNToHashNoPad(t *testing.T) {
 	res := HashNToHashNoPad([]g.GoldilocksField{
 		11295517158488612626,
@@ -196,6 +343,25 @@ func TestHashNToHashNoPadLarge(t *testing.T) {
 	}
 }
 
+func TestHashPad(t *testing.T) {
+	x := []g.GoldilocksField{7}
+	xz := []g.GoldilocksField{7, 0}
+	if HashNoPad(x) != HashNoPad(xz) {
+		t.Fatalf("HashNoPad was expected to ignore trailing zeros")
+	}
+	if HashPad(x) == HashPad(xz) {
+		t.Fatalf("HashPad collides on trailing zeros")
+	}
+	if HashWithLength(x) == HashWithLength(xz) {
+		t.Fatalf("HashWithLength collides on trailing zeros")
+	}
+
+	padded := []g.GoldilocksField{7, 1, 0, 0, 0, 0, 0, 1}
+	if HashPad(x) != HashNoPad(padded) {
+		t.Fatalf("HashPad should pad as plonky2's hash_pad")
+	}
+}
+
 func TestHashTwoToOne(t *testing.T) {
 	input1 := HashOut{
 		3777312593917610528,
@@ -283,3 +449,71 @@ func TestHashToQuinticExtension(t *testing.T) {
 		}
 	}
 }
+
+func TestHashOutMarshal(t *testing.T) {
+	h := HashNToHashNoPad([]g.GoldilocksField{1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	data, err := json.Marshal(struct{ Root HashOut }{h})
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	var out struct{ Root HashOut }
+	if err := json.Unmarshal(data, &out); err != nil || out.Root != h {
+		t.Fatalf("JSON round trip of %s failed: %v", data, err)
+	}
+
+	bin, _ := h.MarshalBinary()
+	var fromBin HashOut
+	if err := fromBin.UnmarshalBinary(bin); err != nil || fromBin != h {
+		t.Fatalf("Binary round trip failed: %v", err)
+	}
+
+	binary.LittleEndian.PutUint64(bin[24:], math.MaxUint64)
+	if err := fromBin.UnmarshalBinary(bin); err == nil {
+		t.Fatalf("Expected non-canonical element to be rejected")
+	}
+	text, _ := h.MarshalText()
+	if err := fromBin.UnmarshalText(text[2:]); err == nil {
+		t.Fatalf("Expected short text to be rejected")
+	}
+}
+
+func TestHashBytes(t *testing.T) {
+	// Same value in both Poseidon2 packages.
+	expected := [4]uint64{6895360622733562179, 6582173705281319175, 1526152403851870125, 470608976463627189}
+	if res := HashBytes([]byte("Hello, Poseidon2!")).ToUint64Array(); res != expected {
+		t.Fatalf("Expected %v, got %v", expected, res)
+	}
+
+	for _, input := range []string{"", "abc", "abcdefgh"} {
+		if HashBytes([]byte(input)) != HashNToHashNoPad(g.PackBytesF([]byte(input))) {
+			t.Fatalf("HashBytes(%q) does not hash the packed bytes", input)
+		}
+		if HashBytes([]byte(input)) == HashBytes([]byte(input+"\x00")) {
+			t.Fatalf("Trailing zero byte does not change HashBytes(%q)", input)
+		}
+	}
+}
+
+func TestPermutationParams(t *testing.T) {
+	input := make([]g.GoldilocksField, 2*RATE+3)
+	for i := range input {
+		input[i] = g.GoldilocksField(i)
+	}
+
+	p, err := NewPermutation(PARAMS_12_PLONKY3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HashOut(p.HashNToHashNoPad(input)) != HashNToHashNoPad(input) {
+		t.Fatalf("PARAMS_12_PLONKY3 should be the instance of the package")
+	}
+
+	wide, err := NewPermutation(PARAMS_20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wide.Params().Rate != 16 || HashOut(wide.HashNToHashNoPad(input)) == HashNToHashNoPad(input) {
+		t.Fatalf("PARAMS_20 should select another instance")
+	}
+}
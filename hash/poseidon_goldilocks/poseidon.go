@@ -0,0 +1,181 @@
+// Package poseidon_goldilocks implements the original Poseidon permutation over
+// Goldilocks with the parameters of plonky2's PoseidonHash: width 12, x^7,
+// 4 + 22 + 4 rounds and the plonky2 MDS matrix and round constants. Hashes are
+// bit-compatible with plonky2, so proofs and Merkle caps produced by unmodified
+// plonky2 can be checked with it.
+package poseidon_goldilocks
+
+import (
+	"fmt"
+	"math/bits"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+)
+
+type HashOut [OUT]g.GoldilocksField
+
+func EmptyHashOut() HashOut {
+	return HashOut{g.ZeroF(), g.ZeroF(), g.ZeroF(), g.ZeroF()}
+}
+
+func (h HashOut) ToLittleEndianBytes() []byte {
+	res := make([]byte, 0, OUT*g.Bytes)
+	for _, elem := range h {
+		res = append(res, g.ToLittleEndianBytesF(elem)...)
+	}
+	return res
+}
+
+func HashOutFromLittleEndianBytes(b []byte) (HashOut, error) {
+	if len(b) != OUT*g.Bytes {
+		return HashOut{}, fmt.Errorf("input bytes len should be 32 but is %d", len(b))
+	}
+
+	gArr, err := g.ArrayFromCanonicalLittleEndianBytesF(b)
+	if err != nil {
+		return HashOut{}, fmt.Errorf("failed to convert bytes to field element. bytes: %v, error: %w", b, err)
+	}
+
+	return HashOut{gArr[0], gArr[1], gArr[2], gArr[3]}, nil
+}
+
+func (h HashOut) ToUint64Array() [OUT]uint64 {
+	return [OUT]uint64{h[0].ToCanonicalUint64(), h[1].ToCanonicalUint64(), h[2].ToCanonicalUint64(), h[3].ToCanonicalUint64()}
+}
+
+func HashOutFromUint64Array(arr [OUT]uint64) HashOut {
+	return HashOut{g.GoldilocksField(arr[0]), g.GoldilocksField(arr[1]), g.GoldilocksField(arr[2]), g.GoldilocksField(arr[3])}
+}
+
+func HashNoPad(input []g.GoldilocksField) HashOut {
+	return HashNToHashNoPad(input)
+}
+
+// plonky2's two_to_one: a single permutation of the concatenation.
+func HashTwoToOne(input1, input2 HashOut) HashOut {
+	return HashNToHashNoPad([]g.GoldilocksField{input1[0], input1[1], input1[2], input1[3], input2[0], input2[1], input2[2], input2[3]})
+}
+
+func HashNToOne(input []HashOut) HashOut {
+	if len(input) == 1 {
+		return input[0]
+	}
+
+	res := HashTwoToOne(input[0], input[1])
+	for i := 2; i < len(input); i++ {
+		res = HashTwoToOne(res, input[i])
+	}
+
+	return res
+}
+
+// plonky2's hash_or_noop: inputs of at most OUT elements are their own hash,
+// padded with zeros, as for the leaves of plonky2 Merkle trees.
+func HashOrNoop(input []g.GoldilocksField) HashOut {
+	if len(input) <= OUT {
+		var res HashOut
+		for i := range input {
+			res[i] = g.GoldilocksField(input[i].ToCanonicalUint64())
+		}
+		return res
+	}
+	return HashNoPad(input)
+}
+
+func HashNToHashNoPad(input []g.GoldilocksField) HashOut {
+	res := HashNToMNoPad(input, OUT)
+	return HashOut{res[0], res[1], res[2], res[3]}
+}
+
+// plonky2's hash_n_to_m_no_pad: the input is absorbed RATE elements at a time by
+// overwriting the rate part of the state, then numOutputs elements are squeezed.
+// The outputs are canonical, so that hashes can be compared with ==.
+func HashNToMNoPad(input []g.GoldilocksField, numOutputs int) []g.GoldilocksField {
+	var perm [WIDTH]g.GoldilocksField
+	for i := 0; i < len(input); i += RATE {
+		for j := 0; j < RATE && i+j < len(input); j++ {
+			perm[j] = input[i+j]
+		}
+		Permute(&perm)
+	}
+
+	outputs := make([]g.GoldilocksField, 0, numOutputs)
+	for {
+		for i := 0; i < RATE; i++ {
+			outputs = append(outputs, g.GoldilocksField(perm[i].ToCanonicalUint64()))
+			if len(outputs) == numOutputs {
+				return outputs
+			}
+		}
+		Permute(&perm)
+	}
+}
+
+// plonky2's hash_pad: HashNoPad of the input with 10*1 padding.
+func HashPad(input []g.GoldilocksField) HashOut {
+	padded := make([]g.GoldilocksField, len(input), (len(input)/RATE+2)*RATE)
+	copy(padded, input)
+	padded = append(padded, g.OneF())
+	for (len(padded)+1)%RATE != 0 {
+		padded = append(padded, g.ZeroF())
+	}
+	padded = append(padded, g.OneF())
+	return HashNoPad(padded)
+}
+
+// The naive form of plonky2's permutation. plonky2 computes the partial rounds
+// with precomputed sparse matrices instead, with the same result.
+func Permute(state *[WIDTH]g.GoldilocksField) {
+	round := 0
+	fullRounds(state, &round)
+	partialRounds(state, &round)
+	fullRounds(state, &round)
+}
+
+func fullRounds(state *[WIDTH]g.GoldilocksField, round *int) {
+	for r := 0; r < HALF_N_FULL_ROUNDS; r++ {
+		constantLayer(state, *round)
+		g.Pow7Vec(state[:], state[:])
+		mdsLayer(state)
+		*round++
+	}
+}
+
+func partialRounds(state *[WIDTH]g.GoldilocksField, round *int) {
+	for r := 0; r < N_PARTIAL_ROUNDS; r++ {
+		constantLayer(state, *round)
+		g.Pow7Vec(state[:1], state[:1])
+		mdsLayer(state)
+		*round++
+	}
+}
+
+func constantLayer(state *[WIDTH]g.GoldilocksField, round int) {
+	for i := range state {
+		state[i] = g.AddF(state[i], g.GoldilocksField(ALL_ROUND_CONSTANTS[WIDTH*round+i]))
+	}
+}
+
+// state[r] = sum_i MDS_MATRIX_CIRC[i] * state[(i+r) % WIDTH] + MDS_MATRIX_DIAG[r] * state[r].
+// The coefficients are small, so the products of each half of the elements sum
+// in 64 bits and a row costs a single reduction.
+func mdsLayer(state *[WIDTH]g.GoldilocksField) {
+	var lo, hi [2 * WIDTH]uint64
+	for i := range state {
+		lo[i] = uint64(state[i]) & 0xffffffff
+		hi[i] = uint64(state[i]) >> 32
+		lo[i+WIDTH], hi[i+WIDTH] = lo[i], hi[i]
+	}
+
+	for r := 0; r < WIDTH; r++ {
+		sumLo := MDS_MATRIX_DIAG[r] * lo[r]
+		sumHi := MDS_MATRIX_DIAG[r] * hi[r]
+		for i := 0; i < WIDTH; i++ {
+			sumLo += MDS_MATRIX_CIRC[i] * lo[i+r]
+			sumHi += MDS_MATRIX_CIRC[i] * hi[i+r]
+		}
+		// sumLo + sumHi * 2^32 as a 128-bit integer.
+		l, c := bits.Add64(sumLo, sumHi<<32, 0)
+		state[r] = g.Reduce128F(sumHi>>32+c, l)
+	}
+}
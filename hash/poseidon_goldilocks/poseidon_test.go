@@ -0,0 +1,241 @@
+package poseidon_goldilocks
+
+import (
+	"math/bits"
+	"testing"
+
+	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
+	"github.com/ppd0705/poseidon_crypto/field/matrix"
+)
+
+// Test vectors of plonky2's poseidon_goldilocks.rs.
+func TestPermute(t *testing.T) {
+	vectors := []struct {
+		input, output [WIDTH]uint64
+	}{
+		{
+			input: [WIDTH]uint64{},
+			output: [WIDTH]uint64{
+				0x3c18a9786cb0b359, 0xc4055e3364a246c3, 0x7953db0ab48808f4, 0xc71603f33a1144ca,
+				0xd7709673896996dc, 0x46a84e87642f44ed, 0xd032648251ee0b3c, 0x1c687363b207df62,
+				0xdf8565563e8045fe, 0x40f5b37ff4254dae, 0xd070f637b431067c, 0x1792b1c4342109d7,
+			},
+		},
+		{
+			input: [WIDTH]uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			output: [WIDTH]uint64{
+				0xd64e1e3efc5b8e9e, 0x53666633020aaa47, 0xd40285597c6a8825, 0x613a4f81e81231d2,
+				0x414754bfebd051f0, 0xcb1f8980294a023f, 0x6eb2a9e4d54a9d0f, 0x1902bc3af467e056,
+				0xf045d5eafdc6021f, 0xe4150f77caaa3be5, 0xc9bfd01d39b50cce, 0x5c0a27fcb0e1459b,
+			},
+		},
+		{
+			input: [WIDTH]uint64{
+				g.ORDER - 1, g.ORDER - 1, g.ORDER - 1, g.ORDER - 1, g.ORDER - 1, g.ORDER - 1,
+				g.ORDER - 1, g.ORDER - 1, g.ORDER - 1, g.ORDER - 1, g.ORDER - 1, g.ORDER - 1,
+			},
+			output: [WIDTH]uint64{
+				0xbe0085cfc57a8357, 0xd95af71847d05c09, 0xcf55a13d33c1c953, 0x95803a74f4530e82,
+				0xfcd99eb30a135df1, 0xe095905e913a3029, 0xde0392461b42919b, 0x7d3260e24e81d031,
+				0x10d3d0465d9deaa0, 0xa87571083dfc2a47, 0xe18263681e9958f8, 0xe28e96f1ae5e60d3,
+			},
+		},
+	}
+
+	for _, v := range vectors {
+		var state [WIDTH]g.GoldilocksField
+		for i := range state {
+			state[i] = g.GoldilocksField(v.input[i])
+		}
+		Permute(&state)
+		for i := range state {
+			if state[i].ToCanonicalUint64() != v.output[i] {
+				t.Fatalf("input %x: expected %x at %d, got %x", v.input, v.output[i], i, state[i].ToCanonicalUint64())
+			}
+		}
+	}
+}
+
+// The constants are the outputs of rand 0.8's gen_range(0..ORDER) on
+// rand_chacha's ChaCha8Rng::seed_from_u64(0).
+func TestRoundConstants(t *testing.T) {
+	rng := newChaCha8(0)
+	for i, c := range ALL_ROUND_CONSTANTS {
+		// Widening multiply with rejection, as UniformInt<u64>::sample_single.
+		for {
+			hi, lo := bits.Mul64(rng.Uint64(), g.ORDER)
+			if lo <= g.ORDER-1 {
+				if hi != c {
+					t.Fatalf("round constant %d: expected %x, got %x", i, hi, c)
+				}
+				break
+			}
+		}
+	}
+}
+
+// The ChaCha stream cipher with 8 rounds, a zero nonce and a 64-bit block
+// counter, read as rand_chacha does: consecutive 32-bit words of the keystream,
+// low word first.
+type chaCha8 struct {
+	key     [8]uint32
+	counter uint64
+	block   [16]uint32
+	index   int
+}
+
+// rand_core's seed_from_u64: the key is filled 32 bits at a time by a PCG32.
+func newChaCha8(seed uint64) *chaCha8 {
+	const (
+		mul = 6364136223846793005
+		inc = 11634580027462260723
+	)
+	r := &chaCha8{index: 16}
+	for i := range r.key {
+		seed = seed*mul + inc
+		xorshifted := uint32(((seed >> 18) ^ seed) >> 27)
+		r.key[i] = bits.RotateLeft32(xorshifted, -int(seed>>59))
+	}
+	return r
+}
+
+func (r *chaCha8) Uint64() uint64 {
+	lo := r.uint32()
+	return uint64(r.uint32())<<32 | uint64(lo)
+}
+
+func (r *chaCha8) uint32() uint32 {
+	if r.index == len(r.block) {
+		r.refill()
+	}
+	r.index++
+	return r.block[r.index-1]
+}
+
+func (r *chaCha8) refill() {
+	in := [16]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574} // "expand 32-byte k"
+	copy(in[4:12], r.key[:])
+	in[12], in[13] = uint32(r.counter), uint32(r.counter>>32)
+
+	x := in
+	quarterRound := func(a, b, c, d int) {
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+	}
+	for i := 0; i < 8; i += 2 {
+		quarterRound(0, 4, 8, 12)
+		quarterRound(1, 5, 9, 13)
+		quarterRound(2, 6, 10, 14)
+		quarterRound(3, 7, 11, 15)
+		quarterRound(0, 5, 10, 15)
+		quarterRound(1, 6, 11, 12)
+		quarterRound(2, 7, 8, 13)
+		quarterRound(3, 4, 9, 14)
+	}
+	for i := range x {
+		r.block[i] = x[i] + in[i]
+	}
+	r.counter++
+	r.index = 0
+}
+
+func TestMDSLayer(t *testing.T) {
+	var circ, diag [WIDTH]g.GoldilocksField
+	for i := range circ {
+		circ[i] = g.GoldilocksField(MDS_MATRIX_CIRC[i])
+		diag[i] = g.GoldilocksField(MDS_MATRIX_DIAG[i])
+	}
+	expected := matrix.Circulant(circ[:]).Add(matrix.Diagonal(diag[:]))
+
+	m := matrix.FromLinearMap(WIDTH, func(v []g.GoldilocksField) []g.GoldilocksField {
+		mdsLayer((*[WIDTH]g.GoldilocksField)(v))
+		return v
+	})
+	if !m.Equals(expected) {
+		t.Fatalf("mdsLayer is not circ(MDS_MATRIX_CIRC) + diag(MDS_MATRIX_DIAG)")
+	}
+	if !m.IsMDS() {
+		t.Fatalf("the Poseidon matrix is not MDS")
+	}
+}
+
+func TestHashNoPad(t *testing.T) {
+	input := make([]g.GoldilocksField, RATE)
+	for i := range input {
+		input[i] = g.GoldilocksField(i)
+	}
+	var state [WIDTH]g.GoldilocksField
+	copy(state[:], input)
+	Permute(&state)
+
+	res := HashNoPad(input)
+	for i := range res {
+		if res[i].ToCanonicalUint64() != state[i].ToCanonicalUint64() {
+			t.Fatalf("expected %x, got %x", state[:OUT], res)
+		}
+	}
+
+	if HashTwoToOne(HashOut(input[:OUT]), HashOut(input[OUT:])) != res {
+		t.Fatalf("HashTwoToOne should hash the concatenation")
+	}
+
+	// Two blocks and more outputs than the rate.
+	long := append(input, 1, 2, 3)
+	outputs := HashNToMNoPad(long, RATE+2)
+	copy(state[:], long[RATE:])
+	Permute(&state)
+	expected := make([]uint64, 0, RATE+2)
+	for i := 0; i < RATE; i++ {
+		expected = append(expected, state[i].ToCanonicalUint64())
+	}
+	Permute(&state)
+	expected = append(expected, state[0].ToCanonicalUint64(), state[1].ToCanonicalUint64())
+	for i := range outputs {
+		if uint64(outputs[i]) != expected[i] {
+			t.Fatalf("expected %x, got %x", expected, outputs)
+		}
+	}
+}
+
+func TestHashOrNoop(t *testing.T) {
+	input := []g.GoldilocksField{1, 2, g.GoldilocksField(g.ORDER + 3)}
+	if res := HashOrNoop(input); res != (HashOut{1, 2, 3, 0}) {
+		t.Fatalf("short inputs should be their own hash, got %x", res)
+	}
+
+	input = append(input, 4, 5)
+	if HashOrNoop(input) != HashNoPad(input) {
+		t.Fatalf("long inputs should be hashed")
+	}
+}
+
+func TestHashPad(t *testing.T) {
+	input := []g.GoldilocksField{7}
+	padded := []g.GoldilocksField{7, 1, 0, 0, 0, 0, 0, 1}
+	if HashPad(input) != HashNoPad(padded) {
+		t.Fatalf("HashPad should pad with 10*1")
+	}
+
+	input = make([]g.GoldilocksField, RATE-1)
+	padded = append(make([]g.GoldilocksField, RATE-1), 1, 0, 0, 0, 0, 0, 0, 0, 1)
+	if HashPad(input) != HashNoPad(padded) {
+		t.Fatalf("HashPad should add a block when the input fills the rate but one")
+	}
+}
+
+func BenchmarkPermute(b *testing.B) {
+	var state [WIDTH]g.GoldilocksField
+	for i := range state {
+		state[i] = g.GoldilocksField(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Permute(&state)
+	}
+}
@@ -0,0 +1,64 @@
+// Package batch holds the batch inversion shared by the field packages, which
+// give it their own operations, and the fan-out of slices over goroutines.
+package batch
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Computes 1/in[i] into res[i] with a single inversion: res[i] first holds the
+// product of all non-zero elements before i, and the inverse of the total
+// product is then propagated back. Zero elements map to zero.
+func InverseChunk[T any](in, res []T, isZero func(*T) bool, mul func(a, b *T) T, inv func(*T) T, one T) {
+	acc := one
+	for i := range in {
+		if isZero(&in[i]) {
+			continue
+		}
+		res[i] = acc
+		acc = mul(&acc, &in[i])
+	}
+
+	acc = inv(&acc)
+
+	var zero T
+	for i := len(in) - 1; i >= 0; i-- {
+		if isZero(&in[i]) {
+			res[i] = zero
+			continue
+		}
+		res[i] = mul(&res[i], &acc)
+		acc = mul(&acc, &in[i])
+	}
+}
+
+// Splits [0, n) into contiguous chunks of at least minChunk elements and calls
+// f on each of them from its own goroutine. Below two chunks, f runs once on
+// the calling goroutine.
+func ParallelChunks(n, minChunk int, f func(start, end int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers == 1 || n < 2*minChunk {
+		f(0, n)
+		return
+	}
+
+	chunk := (n + workers - 1) / workers
+	if chunk < minChunk {
+		chunk = minChunk
+	}
+
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		end := start + chunk
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
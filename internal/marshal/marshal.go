@@ -0,0 +1,54 @@
+// Package marshal holds the text and JSON encodings shared by the
+// encoding.TextMarshaler and json.Marshaler implementations of the public types.
+// Text is the lowercase hex encoding of the binary form, and JSON the text form
+// as a string.
+package marshal
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+func EncodeHex(b []byte) []byte {
+	res := make([]byte, hex.EncodedLen(len(b)))
+	hex.Encode(res, b)
+	return res
+}
+
+// Decodes exactly size bytes of lowercase hex. Upper case digits are rejected so
+// that every value has a single text encoding.
+func DecodeHex(text []byte, size int) ([]byte, error) {
+	if len(text) != hex.EncodedLen(size) {
+		return nil, fmt.Errorf("hex input len should be %d but is %d", hex.EncodedLen(size), len(text))
+	}
+	for _, c := range text {
+		if 'A' <= c && c <= 'F' {
+			return nil, fmt.Errorf("hex input should be lowercase")
+		}
+	}
+
+	res := make([]byte, size)
+	if _, err := hex.Decode(res, text); err != nil {
+		return nil, fmt.Errorf("failed to decode hex: %w", err)
+	}
+	return res, nil
+}
+
+func MarshalJSONText(text []byte) ([]byte, error) {
+	return json.Marshal(string(text))
+}
+
+// Returns the text inside a JSON string, or nil for the JSON null, which by
+// convention leaves the destination untouched.
+func UnmarshalJSONText(data []byte) ([]byte, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON string: %w", err)
+	}
+	return []byte(s), nil
+}
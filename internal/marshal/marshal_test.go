@@ -0,0 +1,44 @@
+package marshal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeHex(t *testing.T) {
+	in := []byte{0x01, 0xab, 0xff}
+	text := EncodeHex(in)
+	if string(text) != "01abff" {
+		t.Fatalf("Expected 01abff, got %s", text)
+	}
+
+	out, err := DecodeHex(text, 3)
+	if err != nil || !bytes.Equal(out, in) {
+		t.Fatalf("Failed to decode %s: %v %v", text, out, err)
+	}
+
+	for _, bad := range []string{"01ABff", "01abf", "01abff00", "0xabff", "01abfg"} {
+		if _, err := DecodeHex([]byte(bad), 3); err == nil {
+			t.Fatalf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestJSONText(t *testing.T) {
+	data, err := MarshalJSONText([]byte("01ab"))
+	if err != nil || string(data) != `"01ab"` {
+		t.Fatalf("Unexpected JSON %s: %v", data, err)
+	}
+
+	text, err := UnmarshalJSONText(data)
+	if err != nil || string(text) != "01ab" {
+		t.Fatalf("Unexpected text %s: %v", text, err)
+	}
+
+	if text, err := UnmarshalJSONText([]byte("null")); err != nil || text != nil {
+		t.Fatalf("Expected null to decode to nil text, got %s %v", text, err)
+	}
+	if _, err := UnmarshalJSONText([]byte("12")); err == nil {
+		t.Fatalf("Expected JSON number to be rejected")
+	}
+}
@@ -0,0 +1,57 @@
+package signature
+
+import (
+	"fmt"
+
+	curve "github.com/ppd0705/poseidon_crypto/curve/ecgfp5"
+	"github.com/ppd0705/poseidon_crypto/internal/marshal"
+)
+
+// The binary form of a Signature is ToBytes, the text form is the hex of the
+// binary one and the JSON form is the text as a string. Decoding rejects scalars
+// that are not below the group order.
+
+func (s Signature) MarshalBinary() ([]byte, error) {
+	return s.ToBytes(), nil
+}
+
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	if len(data) != 80 {
+		return fmt.Errorf("signature length should be 80 but is %d", len(data))
+	}
+	sScalar, err := curve.ScalarElementFromCanonicalLittleEndianBytes(data[:40])
+	if err != nil {
+		return fmt.Errorf("failed to decode s: %w", err)
+	}
+	eScalar, err := curve.ScalarElementFromCanonicalLittleEndianBytes(data[40:])
+	if err != nil {
+		return fmt.Errorf("failed to decode e: %w", err)
+	}
+	*s = Signature{S: sScalar, E: eScalar}
+	return nil
+}
+
+func (s Signature) MarshalText() ([]byte, error) {
+	return marshal.EncodeHex(s.ToBytes()), nil
+}
+
+func (s *Signature) UnmarshalText(text []byte) error {
+	data, err := marshal.DecodeHex(text, 80)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(data)
+}
+
+func (s Signature) MarshalJSON() ([]byte, error) {
+	text, _ := s.MarshalText()
+	return marshal.MarshalJSONText(text)
+}
+
+func (s *Signature) UnmarshalJSON(data []byte) error {
+	text, err := marshal.UnmarshalJSONText(data)
+	if err != nil || text == nil {
+		return err
+	}
+	return s.UnmarshalText(text)
+}
@@ -1,7 +1,9 @@
 package signature
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 
 	curve "github.com/ppd0705/poseidon_crypto/curve/ecgfp5"
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
@@ -64,24 +66,21 @@ func SchnorrPkFromSk(sk curve.ECgFp5Scalar) gFp5.Element {
 }
 
 func SchnorrSignHashedMessage(hashedMsg gFp5.Element, sk curve.ECgFp5Scalar) Signature {
-	// Sample random scalar `k` and compute `r = k * G`
-	k := curve.SampleScalarCrypto()
-	r := curve.GENERATOR_ECgFp5Point.Mul(&k).Encode()
-
-	// Compute `e = H(r || H(m))`, which is a scalar point
-	preImage := make([]g.Element, 5+5)
-	for i, elem := range r.ToBasefieldArray() {
-		preImage[i] = elem
-	}
-	for i, elem := range hashedMsg.ToBasefieldArray() {
-		preImage[i+5] = elem
+	sig, err := SchnorrSignHashedMessageFrom(rand.Reader, hashedMsg, sk)
+	if err != nil {
+		panic("failed to read random bytes into buffer")
 	}
+	return sig
+}
 
-	e := curve.FromGfp5(p2.HashToQuinticExtension(preImage))
-	return Signature{
-		S: k.Sub(*e.Mul(&sk)),
-		E: e,
+// Like SchnorrSignHashedMessage, but samples the nonce `k` from r. r must be a
+// cryptographically secure source: reusing a nonce for two messages leaks sk.
+func SchnorrSignHashedMessageFrom(r io.Reader, hashedMsg gFp5.Element, sk curve.ECgFp5Scalar) (Signature, error) {
+	k, err := curve.SampleScalarFrom(r)
+	if err != nil {
+		return ZERO_SIG, fmt.Errorf("failed to sample nonce: %w", err)
 	}
+	return SchnorrSignHashedMessage2(hashedMsg, sk, k), nil
 }
 
 func SchnorrSignHashedMessage2(hashedMsg gFp5.Element, sk, k curve.ECgFp5Scalar) Signature {
@@ -1,8 +1,12 @@
 package signature
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
+	"golang.org/x/crypto/sha3"
+
 	curve "github.com/ppd0705/poseidon_crypto/curve/ecgfp5"
 	g "github.com/ppd0705/poseidon_crypto/field/goldilocks"
 	gFp5 "github.com/ppd0705/poseidon_crypto/field/goldilocks_quintic_extension"
@@ -22,6 +26,34 @@ func TestSchnorrSignAndVerify(t *testing.T) {
 	}
 }
 
+func TestSchnorrSignHashedMessageFrom(t *testing.T) {
+	sk := curve.SampleScalar(nil)
+	hashedMsg := p2.HashToQuinticExtension(g.RandArray(8))
+
+	sign := func() Signature {
+		xof := sha3.NewShake256()
+		xof.Write([]byte("nonce seed"))
+		sig, err := SchnorrSignHashedMessageFrom(xof, hashedMsg, sk)
+		if err != nil {
+			t.Fatalf("SchnorrSignHashedMessageFrom failed: %v", err)
+		}
+		return sig
+	}
+
+	sig := sign()
+	pk := SchnorrPkFromSk(sk)
+	if !IsSchnorrSignatureValid(&pk, &hashedMsg, sig) {
+		t.Fatalf("Signature is invalid")
+	}
+	if again := sign(); !bytes.Equal(again.ToBytes(), sig.ToBytes()) {
+		t.Fatalf("Expected the same nonce stream to give the same signature")
+	}
+
+	if _, err := SchnorrSignHashedMessageFrom(bytes.NewReader(nil), hashedMsg, sk); err == nil {
+		t.Fatalf("Expected an error on an empty reader")
+	}
+}
+
 func TestComparativeSchnorrSignAndVerify(t *testing.T) {
 	sks := []curve.ECgFp5Scalar{
 		curve.ECgFp5Scalar{
@@ -158,3 +190,45 @@ func TestBytes(t *testing.T) {
 		t.Fatalf("Signature is invalid")
 	}
 }
+
+func TestSignatureMarshal(t *testing.T) {
+	sk := curve.SampleScalarCrypto()
+	hashedMsg := p2.HashToQuinticExtension(g.RandArray(8))
+	sig := SchnorrSignHashedMessage(hashedMsg, sk)
+
+	data, err := json.Marshal(struct {
+		Sig Signature `json:"sig"`
+	}{sig})
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	var out struct {
+		Sig Signature `json:"sig"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil || !out.Sig.S.Equals(&sig.S) || !out.Sig.E.Equals(&sig.E) {
+		t.Fatalf("JSON round trip of %s failed: %v", data, err)
+	}
+
+	bin, _ := sig.MarshalBinary()
+	var fromBin Signature
+	if err := fromBin.UnmarshalBinary(bin); err != nil || !fromBin.S.Equals(&sig.S) || !fromBin.E.Equals(&sig.E) {
+		t.Fatalf("Binary round trip failed: %v", err)
+	}
+
+	// Adding the group order to E gives another encoding of the same signature.
+	malleated := Signature{S: sig.S, E: sig.E.AddInner(curve.N)}
+	if err := fromBin.UnmarshalBinary(malleated.ToBytes()); err == nil {
+		t.Fatalf("Expected non-canonical signature to be rejected")
+	}
+	if err := fromBin.UnmarshalBinary(bin[:79]); err == nil {
+		t.Fatalf("Expected 79 bytes to be rejected")
+	}
+}
+
+func BenchmarkSchnorrPkFromSk(b *testing.B) {
+	sk := curve.SampleScalarCrypto()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		SchnorrPkFromSk(sk)
+	}
+}